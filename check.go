@@ -0,0 +1,406 @@
+package go2ts
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Diff describes the differences between existing output, e.g. a committed .ts file, and the output
+// generated by Render(). See Check().
+type Diff struct {
+	// Added holds the qualified names of the declarations in the generated output that are missing
+	// from the existing output, in order of appearance, e.g. "api.v1.Job".
+	Added []string
+
+	// Removed holds the qualified names of the declarations in the existing output that are missing
+	// from the generated output, in order of appearance.
+	Removed []string
+
+	// Changed holds the qualified names of the declarations in both outputs whose code differs, in
+	// order of appearance in the generated output.
+	Changed []string
+
+	// Unified is a unified diff from the existing output to the generated output, with three lines of
+	// context, e.g. "--- existing\n+++ generated\n@@ -3,4 +3,5 @@\n...".
+	Unified string
+}
+
+// String returns the names of the added, removed and changed declarations, if any, followed by the
+// unified diff.
+func (d *Diff) String() string {
+	var sb strings.Builder
+	for _, section := range []struct {
+		label string
+		names []string
+	}{
+		{"added", d.Added},
+		{"removed", d.Removed},
+		{"changed", d.Changed},
+	} {
+		if len(section.names) > 0 {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", section.label, strings.Join(section.names, ", ")))
+		}
+	}
+	sb.WriteString(d.Unified)
+	return sb.String()
+}
+
+// Check renders the TypeScript definitions in memory, as per Render(), and compares them with the
+// given existing output, e.g. a committed .ts file, such that CI can detect whether said file is
+// stale. It returns nil if the outputs are identical, or a *Diff otherwise.
+//
+// In accumulated-errors mode, Check returns the accumulated errors if there are any. See
+// AccumulateErrors().
+func (g *Go2TS) Check(existing io.Reader) (*Diff, error) {
+	var generated bytes.Buffer
+	if err := g.Render(&generated); err != nil {
+		return nil, err
+	}
+	existingBytes, err := io.ReadAll(existing)
+	if err != nil {
+		return nil, err
+	}
+	return CompareOutput(string(existingBytes), generated.String()), nil
+}
+
+// CompareOutput compares the given existing and generated outputs, and returns nil if they are
+// identical, or a *Diff otherwise. See Check().
+//
+// Declarations are identified by the "export" statements in TypeScript code as written by Render()
+// or zod.Render(), e.g. "export interface Job" in "export namespace api" is named "api.Job". Any
+// other differences, e.g. in the header comment or in JSON Schema documents, only appear in the
+// unified diff.
+func CompareOutput(existing, generated string) *Diff {
+	if existing == generated {
+		return nil
+	}
+
+	existingLines, generatedLines := splitLines(existing), splitLines(generated)
+	diff := &Diff{
+		Unified: unifiedDiff("existing", "generated", diffLines(existingLines, generatedLines)),
+	}
+
+	existingDeclarations, existingNames := declarationsByName(existingLines)
+	generatedDeclarations, generatedNames := declarationsByName(generatedLines)
+	for _, name := range generatedNames {
+		code, ok := existingDeclarations[name]
+		if !ok {
+			diff.Added = append(diff.Added, name)
+		} else if code != generatedDeclarations[name] {
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+	for _, name := range existingNames {
+		if _, ok := generatedDeclarations[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	return diff
+}
+
+var (
+	// namespaceRegexp matches the first line of a namespace block, and captures its indentation and
+	// identifier.
+	namespaceRegexp = regexp.MustCompile(`^(\s*)export namespace ([\w$]+) \{$`)
+
+	// declarationRegexp matches the first line of a declaration, and captures its identifier.
+	declarationRegexp = regexp.MustCompile(`^\s*export (?:declare )?(?:interface|type|enum|const|function|class) ([\w$]+)`)
+)
+
+// declarationsByName splits the given lines of TypeScript code into declarations, and returns their
+// code indexed by qualified name, and their qualified names in order of appearance.
+//
+// Declarations are runs of lines separated by blank lines or namespace blocks, including any doc
+// comments, named after their first "export" statement. Thus, e.g. an enum declared as a const
+// object and its type alias count as a single declaration.
+func declarationsByName(lines []string) (map[string]string, []string) {
+	declarations := map[string]string{}
+	var names []string
+
+	type namespace struct {
+		indentation, identifier string
+	}
+	var namespaces []namespace
+
+	var run []string
+	endRun := func() {
+		defer func() { run = nil }()
+		for _, line := range run {
+			match := declarationRegexp.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			var qualifiedName []string
+			for _, ns := range namespaces {
+				qualifiedName = append(qualifiedName, ns.identifier)
+			}
+			name := strings.Join(append(qualifiedName, match[1]), ".")
+			if _, ok := declarations[name]; !ok {
+				declarations[name] = strings.Join(run, "")
+				names = append(names, name)
+			}
+			return
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimRight(line, "\r\n")
+		if match := namespaceRegexp.FindStringSubmatch(trimmed); match != nil {
+			endRun()
+			namespaces = append(namespaces, namespace{indentation: match[1], identifier: match[2]})
+			continue
+		}
+		if len(namespaces) > 0 && trimmed == namespaces[len(namespaces)-1].indentation+"}" {
+			endRun()
+			namespaces = namespaces[:len(namespaces)-1]
+			continue
+		}
+		if strings.TrimSpace(trimmed) == "" {
+			endRun()
+			continue
+		}
+		run = append(run, line)
+	}
+	endRun()
+	return declarations, names
+}
+
+// splitLines splits the given text into lines, including their line endings. The last line lacks a
+// line ending if the text doesn't end with one.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffOp is an operation of an edit script that transforms one list of lines into another.
+type diffOp struct {
+	// kind is ' ' for a line in both lists, '-' for a removed line, or '+' for an added line.
+	kind byte
+
+	// line is the line, including its line ending.
+	line string
+}
+
+// diffLines returns the shortest edit script that transforms a into b, as per the linear space
+// variant of Myers' diff algorithm, which takes O((N+M)D) time and O(N+M) space, where N and M are
+// the lengths of a and b and D is the number of changes. See http://www.xmailserver.org/diff2.pdf.
+func diffLines(a, b []string) []diffOp {
+	ops := appendDiffOps(nil, a, b)
+
+	// Removed lines precede added lines within each run of changes, as is customary.
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		end := i
+		for end < len(ops) && ops[end].kind != ' ' {
+			end++
+		}
+		sort.SliceStable(ops[i:end], func(j, k int) bool {
+			return ops[i+j].kind == '-' && ops[i+k].kind == '+'
+		})
+		i = end
+	}
+	return ops
+}
+
+// appendDiffOps appends the shortest edit script that transforms a into b to ops, and returns the
+// extended slice.
+func appendDiffOps(ops []diffOp, a, b []string) []diffOp {
+	// Lines in common at the start and the end are left out of the search.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+	a, b = a[prefix:], b[prefix:]
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			ops = append(ops, diffOp{kind: '+', line: line})
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			ops = append(ops, diffOp{kind: '-', line: line})
+		}
+	default:
+		// Both a and b are non-empty and differ in their first and last lines, thus at least two
+		// changes are needed, and the split point found is neither (0, 0) nor the end of a and b.
+		x, y := diffSplitPoint(a, b)
+		ops = appendDiffOps(ops, a[:x], b[:y])
+		ops = appendDiffOps(ops, a[x:], b[y:])
+	}
+
+	for _, line := range common {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+	return ops
+}
+
+// diffSplitPoint returns a point (x, y) through which a shortest edit script that transforms a into
+// b passes, such that said script is the concatenation of shortest edit scripts that transform
+// a[:x] into b[:y] and a[x:] into b[y:].
+//
+// It searches forward from the start and backward from the end of a and b at once, and returns the
+// point at which the furthest reaching paths of both searches overlap, i.e. the middle of a
+// shortest edit script.
+func diffSplitPoint(a, b []string) (int, int) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+
+	// forward holds the furthest x reached on each diagonal k = x - y from the start, at index
+	// k+maxD+1, and backward the furthest number of lines reached from the end of a on each diagonal
+	// k = (n - x) - (m - y), or -1 for diagonals that haven't been reached yet.
+	forward, backward := make([]int, 2*maxD+3), make([]int, 2*maxD+3)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[maxD+2], backward[maxD+2] = 0, 0
+
+	// Diagonals whose paths leave a and b are skipped in later steps, e.g. the furthest diagonals
+	// once x exceeds n.
+	delta := n - m
+	odd := delta%2 != 0
+	forwardStart, forwardEnd, backwardStart, backwardEnd := 0, 0, 0, 0
+
+	// furthest returns the furthest x reached on diagonal k at step d, given the furthest x reached
+	// on each diagonal at step d-1, before following the diagonal.
+	furthest := func(v []int, k, d int) int {
+		if k == -d || (k != d && v[k+maxD] < v[k+maxD+2]) {
+			return v[k+maxD+2]
+		}
+		return v[k+maxD] + 1
+	}
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d + forwardStart; k <= d-forwardEnd; k += 2 {
+			x := furthest(forward, k, d)
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[k+maxD+1] = x
+			switch {
+			case x > n:
+				forwardEnd += 2
+			case y > m:
+				forwardStart += 2
+			case odd:
+				if i := delta - k + maxD + 1; i >= 0 && i < len(backward) && backward[i] != -1 && x >= n-backward[i] {
+					return x, y
+				}
+			}
+		}
+
+		for k := -d + backwardStart; k <= d-backwardEnd; k += 2 {
+			x := furthest(backward, k, d)
+			y := x - k
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[k+maxD+1] = x
+			switch {
+			case x > n:
+				backwardEnd += 2
+			case y > m:
+				backwardStart += 2
+			case !odd:
+				if i := delta - k + maxD + 1; i >= 0 && i < len(forward) && forward[i] != -1 && forward[i] >= n-x {
+					return n - x, m - y
+				}
+			}
+		}
+	}
+
+	// Unreachable, as the searches overlap after at most maxD steps.
+	return n, m
+}
+
+// unifiedDiff formats the given edit script as a unified diff with three lines of context between
+// the files with the given names, or returns the empty string if there are no changes.
+func unifiedDiff(fromName, toName string, ops []diffOp) string {
+	const context = 3
+
+	// Line numbers (0-based) in both files before each operation.
+	fromLines, toLines := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for i, op := range ops {
+		fromLines[i+1], toLines[i+1] = fromLines[i], toLines[i]
+		if op.kind != '+' {
+			fromLines[i+1]++
+		}
+		if op.kind != '-' {
+			toLines[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(ops); {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		// Extend the hunk until the next change is more than two contexts away.
+		end := i + 1
+		for j := i; j < len(ops) && j-end <= 2*context; j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			}
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		if end += context; end > len(ops) {
+			end = len(ops)
+		}
+
+		if sb.Len() == 0 {
+			sb.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+		}
+		sb.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(fromLines[start], fromLines[end]), hunkRange(toLines[start], toLines[end])))
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+// hunkRange returns the range of a unified diff hunk given its first line (0-based) and the line
+// after its last line, e.g. "3,4" for lines 3 to 6 (1-based).
+func hunkRange(start, end int) string {
+	switch end - start {
+	case 0:
+		// Empty ranges start at the line before the hunk.
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, end-start)
+}
@@ -0,0 +1,503 @@
+// The go2ts command writes TypeScript definitions for the Go types declared in the given packages.
+//
+// Unlike the go2ts package, which requires writing a Go program that imports the Go types of
+// interest, the go2ts command loads the Go packages from source code. Thus, it can generate
+// TypeScript definitions for Go types that aren't exported, e.g. types in "main" packages.
+//
+// Usage:
+//
+//	go2ts [flags] [packages]
+//
+// Packages are specified using the same patterns as the go command, e.g. "./...", and default to
+// the package in the current directory.
+//
+// By default, the go2ts command writes TypeScript definitions for all Go types annotated with a
+// //go2ts:export comment, e.g.:
+//
+//	//go2ts:export
+//	type Turtle struct {
+//		Position Position
+//	}
+//
+// Alternatively, the -type flag can be used to select Go types by name. As with the go2ts package,
+// any types reachable from the selected types will also be written.
+//
+// Named Go types with constants, e.g. "type Direction string" with "const Up Direction = "up"", are
+// declared as TypeScript union types of the values of their constants, e.g.
+// "export type Direction = 'up' | 'down'", unless the -unions=false flag is provided.
+//
+// The doc comments of Go types and struct fields are written as JSDoc comments, unless the
+// -docs=false flag is provided.
+//
+// Generic Go types are declared as generic TypeScript types, e.g. "export interface Page<T>", and
+// referenced with their type arguments, e.g. "Page<Item>", unless the -generics=false flag is
+// provided, in which case each instantiation is declared separately, e.g. "PageItem".
+//
+// The -guards flag additionally writes a type guard function for each TypeScript type, e.g.
+// "export function isTurtle(x: unknown): x is Turtle", which validates values at runtime.
+//
+// The -format flag selects the output format: "typescript" (the default) for TypeScript type
+// declarations, "zod" for zod schemas (see the zod package), "jsonschema" for a JSON Schema
+// document (see the jsonschema package), or "ast" for a JSON encoding of the TypeScript type
+// declarations (see typescript.MarshalTypeDeclarations()), e.g. to compare them across commits.
+//
+// TypeScript types are written in the order they are found, unless the -sort flag is provided:
+// "name" sorts them by qualified name, and "package" by Go package and then by qualified name,
+// which keeps the output stable as Go types are reordered.
+//
+// The -outdir flag writes TypeScript definitions as ES modules to the given directory instead, one
+// .ts file per Go package, or per TypeScript namespace if the -groupby=namespace flag is provided.
+// References to types in other modules are imported, e.g. "import type { Job } from './jobs';". It
+// cannot be used with the -o flag.
+//
+// The output is indented with tabs, with single quotes and semicolons. To match the configuration
+// of a code formatter such as prettier instead, use e.g. the -indent=2, -quotes=double and
+// -semicolons=false flags. The -header flag replaces the comment at the top of each output file,
+// and the -crlf flag writes Windows line endings.
+//
+// The -check flag compares the TypeScript definitions with the existing -o file instead of writing
+// it, and exits with an error listing the added, removed and changed declarations, followed by a
+// unified diff, if the file is stale. This is useful to detect drift in CI.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/skia-dev/go2ts"
+	"github.com/skia-dev/go2ts/jsonschema"
+	"github.com/skia-dev/go2ts/loader"
+	"github.com/skia-dev/go2ts/typescript"
+	"github.com/skia-dev/go2ts/zod"
+	"golang.org/x/tools/go/packages"
+)
+
+// exportMarker is the comment used to select Go types when the -type flag is not provided.
+const exportMarker = "//go2ts:export"
+
+// options holds the command-line options of the go2ts command.
+type options struct {
+	// typeNames holds the names of the Go types to export. If empty, Go types annotated with
+	// exportMarker are exported.
+	typeNames []string
+
+	// namespace is the TypeScript namespace to add the selected Go types to, if non-empty.
+	namespace string
+
+	// ignoreNil determines whether nillable Go types should be treated as their non-nillable
+	// counterparts. See go2ts.Go2TS.AddIgnoreNil().
+	ignoreNil bool
+
+	// constUnions determines whether named Go types with constants should be declared as TypeScript
+	// union types. See go2ts.Go2TS.DiscoverConstUnions().
+	constUnions bool
+
+	// docComments determines whether the doc comments of Go types and struct fields should be written
+	// as JSDoc comments. See go2ts.Go2TS.EmitDocComments().
+	docComments bool
+
+	// typeGuards determines whether type guard functions should be written. See
+	// go2ts.Go2TS.EmitTypeGuards().
+	typeGuards bool
+
+	// readonly determines whether interface properties, arrays and maps should be declared as
+	// readonly. See go2ts.Go2TS.EmitReadonly().
+	readonly bool
+
+	// maxTuple is the maximum length of the Go arrays declared as TypeScript tuples. See
+	// go2ts.Go2TS.SetMaxTupleLength().
+	maxTuple int
+
+	// generics determines whether generic Go types should be declared as generic TypeScript types.
+	// See go2ts.Go2TS.EmitGenerics().
+	generics bool
+
+	// format is the output format, e.g. formatTypeScript.
+	format string
+
+	// outDir is the directory to write one ES module per group of TypeScript types to, if non-empty.
+	// See go2ts.Go2TS.RenderModules().
+	outDir string
+
+	// groupBy determines how TypeScript types are grouped into ES modules, e.g. groupByPackage.
+	groupBy string
+
+	// sort determines the order of the TypeScript types, e.g. sortByName. See
+	// go2ts.Go2TS.SetSortOrder().
+	sort string
+
+	// indent is the number of spaces per indentation level, or 0 to indent with tabs.
+	indent int
+
+	// quotes is the quote style of string literals, e.g. quotesDouble.
+	quotes string
+
+	// omitSemicolons determines whether the semicolons at the end of statements are omitted.
+	omitSemicolons bool
+
+	// header is the comment at the top of each output file, if non-empty.
+	header string
+
+	// crlf determines whether output files have Windows line endings.
+	crlf bool
+}
+
+// Output formats supported by the -format flag.
+const (
+	formatTypeScript = "typescript"
+	formatZod        = "zod"
+	formatJSONSchema = "jsonschema"
+	formatAST        = "ast"
+)
+
+// Sort orders supported by the -sort flag.
+const (
+	sortByAddedOrder = "added"
+	sortByName       = "name"
+	sortByPackage    = "package"
+)
+
+// Quote styles supported by the -quotes flag.
+const (
+	quotesSingle = "single"
+	quotesDouble = "double"
+)
+
+// Module groupings supported by the -groupby flag.
+const (
+	groupByPackage   = "package"
+	groupByNamespace = "namespace"
+)
+
+func main() {
+	var (
+		typeNames   = flag.String("type", "", "Comma-separated list of Go type names to export. If empty, Go types annotated with a "+exportMarker+" comment will be exported.")
+		namespace   = flag.String("namespace", "", "TypeScript namespace to add the exported types to.")
+		ignoreNil   = flag.Bool("ignorenil", false, "Treat nillable Go types (e.g. slices, maps, pointers) as non-nillable.")
+		constUnions = flag.Bool("unions", true, "Declare named Go types as TypeScript union types of the values of their constants, if any.")
+		docComments = flag.Bool("docs", true, "Write the doc comments of Go types and struct fields as JSDoc comments.")
+		typeGuards  = flag.Bool("guards", false, "Write a type guard function (e.g. isTurtle) for each TypeScript type.")
+		readonly    = flag.Bool("readonly", false, "Declare all interface properties, arrays and maps as readonly.")
+		maxTuple    = flag.Int("maxtuple", 0, "Maximum length of the Go arrays declared as TypeScript tuples, e.g. [number, number, number]. If 0, Go arrays are declared as TypeScript arrays.")
+		generics    = flag.Bool("generics", true, "Declare generic Go types as generic TypeScript types.")
+		format      = flag.String("format", formatTypeScript, "Output format: "+formatTypeScript+", "+formatZod+", "+formatJSONSchema+" or "+formatAST+".")
+		output      = flag.String("o", "", "Output file. If empty, TypeScript definitions will be written to stdout.")
+		outDir      = flag.String("outdir", "", "Output directory. If non-empty, TypeScript definitions will be written to it as ES modules, one per group of types. Cannot be used with -o.")
+		groupBy     = flag.String("groupby", groupByPackage, "How to group TypeScript definitions into ES modules with -outdir: "+groupByPackage+" or "+groupByNamespace+".")
+		sort        = flag.String("sort", sortByAddedOrder, "Order of the TypeScript definitions: "+sortByAddedOrder+", "+sortByName+" or "+sortByPackage+".")
+		indent      = flag.Int("indent", 0, "Number of spaces per indentation level. If 0, TypeScript definitions will be indented with tabs.")
+		quotes      = flag.String("quotes", quotesSingle, "Quotes around string literals: "+quotesSingle+" or "+quotesDouble+".")
+		semicolons  = flag.Bool("semicolons", true, "Terminate statements and interface properties with semicolons.")
+		header      = flag.String("header", "", "Comment at the top of each output file, e.g. a license banner. If empty, a DO NOT EDIT comment will be written.")
+		crlf        = flag.Bool("crlf", false, "Write Windows (CRLF) line endings.")
+		check       = flag.Bool("check", false, "Compare the TypeScript definitions with the existing output file instead of writing it, and exit with an error listing the differences if they differ.")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: go2ts [flags] [packages]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	opts := options{
+		namespace:   *namespace,
+		ignoreNil:   *ignoreNil,
+		constUnions: *constUnions,
+		docComments: *docComments,
+		typeGuards:  *typeGuards,
+		readonly:    *readonly,
+		maxTuple:    *maxTuple,
+		generics:    *generics,
+		format:      *format,
+		outDir:      *outDir,
+		groupBy:     *groupBy,
+		sort:        *sort,
+		indent:      *indent,
+		quotes:      *quotes,
+		header:      *header,
+		crlf:        *crlf,
+	}
+	opts.omitSemicolons = !*semicolons
+	if *typeNames != "" {
+		opts.typeNames = strings.Split(*typeNames, ",")
+	}
+	if *output != "" && *outDir != "" {
+		fmt.Fprintf(os.Stderr, "go2ts: -o cannot be used with -outdir\n")
+		os.Exit(2)
+	}
+	if *check && (*output == "" || *outDir != "") {
+		fmt.Fprintf(os.Stderr, "go2ts: -check requires -o and cannot be used with -outdir\n")
+		os.Exit(2)
+	}
+
+	var b bytes.Buffer
+	if err := generate(&b, os.Stderr, "", flag.Args(), opts); err != nil {
+		fmt.Fprintf(os.Stderr, "go2ts: %s\n", err)
+		os.Exit(1)
+	}
+	if *outDir != "" {
+		return
+	}
+	if *check {
+		if err := checkOutput(*output, b.Bytes()); err != nil {
+			fmt.Fprintf(os.Stderr, "go2ts: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *output == "" {
+		_, err := os.Stdout.Write(b.Bytes())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "go2ts: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := os.WriteFile(*output, b.Bytes(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "go2ts: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// generate loads the Go packages matching the given patterns, relative to the given directory, and
+// writes TypeScript definitions for the Go types selected according to the given options. Any
+// warnings are written to warningsW.
+func generate(w, warningsW io.Writer, dir string, patterns []string, opts options) error {
+	pkgs, err := loadPackages(dir, patterns)
+	if err != nil {
+		return err
+	}
+
+	typeNames, err := selectTypes(pkgs, opts.typeNames)
+	if err != nil {
+		return err
+	}
+
+	generator := go2ts.New()
+	generator.AccumulateErrors()
+
+	// Reuse the loaded packages, including any dependencies, whenever the generator needs to inspect
+	// source code.
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		generator.UsePackage(pkg.Types, pkg.Syntax)
+	})
+
+	if opts.constUnions {
+		generator.DiscoverConstUnions()
+	}
+	if opts.docComments {
+		generator.EmitDocComments()
+	}
+	if opts.typeGuards {
+		generator.EmitTypeGuards()
+	}
+	if opts.readonly {
+		generator.EmitReadonly()
+	}
+	if opts.maxTuple > 0 {
+		generator.SetMaxTupleLength(opts.maxTuple)
+	}
+	if opts.generics {
+		generator.EmitGenerics()
+	}
+	switch opts.sort {
+	case sortByAddedOrder, "":
+	case sortByName:
+		generator.SetSortOrder(go2ts.SortByQualifiedName)
+	case sortByPackage:
+		generator.SetSortOrder(go2ts.SortByPackage)
+	default:
+		return fmt.Errorf("unknown sort order %q", opts.sort)
+	}
+	renderOptions, err := makeRenderOptions(opts)
+	if err != nil {
+		return err
+	}
+	generator.SetRenderOptions(renderOptions)
+	for _, typeName := range typeNames {
+		if opts.ignoreNil {
+			generator.AddToNamespaceIgnoreNil(typeName.Type(), opts.namespace)
+		} else {
+			generator.AddToNamespace(typeName.Type(), opts.namespace)
+		}
+	}
+
+	for _, warning := range generator.Warnings() {
+		fmt.Fprintf(warningsW, "go2ts: warning: %s\n", warning)
+	}
+
+	if opts.outDir != "" {
+		return renderModules(generator, opts)
+	}
+
+	switch opts.format {
+	case formatTypeScript, "":
+		return generator.Render(w)
+	case formatZod:
+		if err := generator.Err(); err != nil {
+			return err
+		}
+		return zod.RenderWithOptions(w, generator.TypeDeclarations(), renderOptions)
+	case formatJSONSchema:
+		if err := generator.Err(); err != nil {
+			return err
+		}
+		return jsonschema.Render(w, generator.TypeDeclarations())
+	case formatAST:
+		if err := generator.Err(); err != nil {
+			return err
+		}
+		data, err := typescript.MarshalTypeDeclarations(generator.TypeDeclarations())
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	default:
+		return fmt.Errorf("unknown format %q", opts.format)
+	}
+}
+
+// checkOutput compares the given generated output with the contents of the given file, which is
+// treated as empty if it doesn't exist, and returns an error listing the differences if they
+// differ. See go2ts.CompareOutput().
+func checkOutput(filename string, generated []byte) error {
+	existing, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if diff := go2ts.CompareOutput(string(existing), string(generated)); diff != nil {
+		return fmt.Errorf("%s is out of date:\n%s", filename, strings.TrimSuffix(diff.String(), "\n"))
+	}
+	return nil
+}
+
+// makeRenderOptions returns the render options for the given options. See
+// go2ts.Go2TS.SetRenderOptions().
+func makeRenderOptions(opts options) (typescript.RenderOptions, error) {
+	renderOptions := typescript.RenderOptions{
+		OmitSemicolons: opts.omitSemicolons,
+		Header:         opts.header,
+	}
+	if opts.indent < 0 {
+		return renderOptions, fmt.Errorf("invalid indentation %d", opts.indent)
+	}
+	renderOptions.Indent = strings.Repeat(" ", opts.indent)
+	switch opts.quotes {
+	case quotesSingle, "":
+	case quotesDouble:
+		renderOptions.Quotes = typescript.DoubleQuotes
+	default:
+		return renderOptions, fmt.Errorf("unknown quote style %q", opts.quotes)
+	}
+	if opts.crlf {
+		renderOptions.LineEnding = "\r\n"
+	}
+	return renderOptions, nil
+}
+
+// renderModules writes the TypeScript definitions of the given generator to opts.outDir as ES
+// modules, grouped according to the given options.
+func renderModules(generator *go2ts.Go2TS, opts options) error {
+	if opts.format != formatTypeScript && opts.format != "" {
+		return fmt.Errorf("format %q cannot be written as ES modules", opts.format)
+	}
+	switch opts.groupBy {
+	case groupByPackage, "":
+		return generator.RenderModules(opts.outDir, go2ts.GroupByPackage)
+	case groupByNamespace:
+		return generator.RenderModules(opts.outDir, go2ts.GroupByNamespace)
+	default:
+		return fmt.Errorf("unknown grouping %q", opts.groupBy)
+	}
+}
+
+// loadPackages loads the Go packages matching the given patterns, including their syntax trees.
+func loadPackages(dir string, patterns []string) ([]*packages.Package, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	cfg := &packages.Config{
+		Mode: loader.Mode,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []string
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, err := range pkg.Errors {
+			errs = append(errs, err.Error())
+		}
+	})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to load packages:\n%s", strings.Join(errs, "\n"))
+	}
+	return pkgs, nil
+}
+
+// selectTypes returns the Go types with the given names in the order they are declared. If no names
+// are given, it returns the Go types annotated with exportMarker.
+func selectTypes(pkgs []*packages.Package, names []string) ([]*types.TypeName, error) {
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[name] = true
+	}
+	found := map[string]bool{}
+
+	var typeNames []*types.TypeName
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					if len(names) > 0 && !wanted[typeSpec.Name.Name] {
+						continue
+					}
+					if len(names) == 0 && !hasExportMarker(genDecl.Doc) && !hasExportMarker(typeSpec.Doc) {
+						continue
+					}
+					typeName, ok := pkg.Types.Scope().Lookup(typeSpec.Name.Name).(*types.TypeName)
+					if !ok {
+						continue
+					}
+					found[typeSpec.Name.Name] = true
+					typeNames = append(typeNames, typeName)
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		if !found[name] {
+			return nil, fmt.Errorf("type %q not found", name)
+		}
+	}
+	return typeNames, nil
+}
+
+// hasExportMarker returns true if the given comment group includes exportMarker.
+func hasExportMarker(commentGroup *ast.CommentGroup) bool {
+	if commentGroup == nil {
+		return false
+	}
+	for _, comment := range commentGroup.List {
+		if strings.TrimSpace(comment.Text) == exportMarker {
+			return true
+		}
+	}
+	return false
+}
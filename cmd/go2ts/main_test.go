@@ -0,0 +1,443 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/skia-dev/go2ts/typescript"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_ExportMarker_Success(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{constUnions: true})
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Position {
+	X: number;
+	Y: number;
+}
+
+export interface Turtle {
+	Coordinates: Position;
+	Direction: direction;
+	Speed: speed;
+	Born: string;
+	Tags?: string[] | null;
+	Parent: Turtle | null;
+}
+
+export interface Pond {
+	Turtles: { [key: string]: Turtle } | null;
+}
+
+export type direction = 'up' | 'down';
+
+export type speed = 1 | 2;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestGenerate_TypeNamesNamespaceAndIgnoreNil_Success(t *testing.T) {
+	var b bytes.Buffer
+	var warnings bytes.Buffer
+	err := generate(&b, &warnings, "", []string{"./testdata/example"}, options{
+		typeNames: []string{"Lake"},
+		namespace: "water",
+		ignoreNil: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "go2ts: warning: Lake.Depth: Unknown json tag option \"omitempy\"\n", warnings.String())
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export namespace water {
+	export interface Position {
+		X: number;
+		Y: number;
+	}
+
+	export interface Turtle {
+		Coordinates: water.Position;
+		Direction: water.direction;
+		Speed: water.speed;
+		Born: string;
+		Tags?: string[];
+		Parent: water.Turtle;
+	}
+
+	export interface Pond {
+		Turtles: { [key: string]: water.Turtle };
+	}
+
+	export interface Lake {
+		Ponds: water.Pond[];
+		depth: number;
+	}
+
+	export type direction = string;
+
+	export type speed = number;
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestGenerate_DocComments_Success(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{
+		typeNames:   []string{"Turtle"},
+		constUnions: true,
+		docComments: true,
+	})
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Position {
+	X: number;
+	Y: number;
+}
+
+/** Turtle is exported via the go2ts:export marker. */
+export interface Turtle {
+	Coordinates: Position;
+	Direction: direction;
+	Speed: speed;
+	/** Born is when the turtle hatched. */
+	Born: string;
+	/** Tags are free-form labels. */
+	Tags?: string[] | null;
+	Parent: Turtle | null;
+}
+
+export type direction = 'up' | 'down';
+
+/** speed is an iota-based enum. */
+export type speed = 1 | 2;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestGenerate_TypeGuards_Success(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{
+		typeNames:   []string{"Lake"},
+		constUnions: true,
+		typeGuards:  true,
+		ignoreNil:   true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `
+export function isLake(x: unknown): x is Lake {
+	if (typeof x !== 'object' || x === null || Array.isArray(x)) {
+		return false;
+	}
+	const o = x as Record<string, unknown>;
+	return (
+		(Array.isArray(o['Ponds']) && o['Ponds'].every(($v1) => isPond($v1))) &&
+		typeof o['depth'] === 'number'
+	);
+}
+`)
+	assert.Contains(t, b.String(), `
+export function is_speed(x: unknown): x is speed {`)
+}
+
+func TestGenerate_Readonly_Success(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{
+		typeNames: []string{"Lake"},
+		readonly:  true,
+		ignoreNil: true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `
+export interface Lake {
+	readonly Ponds: readonly Pond[];
+`)
+}
+
+func TestGenerate_MaxTuple_ShortArraysDeclaredAsTuples(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{
+		typeNames: []string{"Shore"},
+		maxTuple:  3,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `
+export interface Shore {
+	Start: [number, number];
+	Samples: number[];
+}
+`)
+}
+
+func TestGenerate_Generics_Success(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{
+		typeNames: []string{"Sea"},
+		ignoreNil: true,
+		generics:  true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `
+export interface Page<T> {
+	Items: T[];
+	Total: number;
+}
+`)
+	assert.Contains(t, b.String(), `
+export interface Sea {
+	Lakes: Page<Lake>;
+}
+`)
+
+	b.Reset()
+	err = generate(&b, io.Discard, "", []string{"./testdata/example"}, options{
+		typeNames: []string{"Sea"},
+		ignoreNil: true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `
+export interface PageLake {
+	Items: Lake[];
+	Total: number;
+}
+`)
+}
+
+func TestGenerate_ZodFormat_Success(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{
+		typeNames:   []string{"Pond"},
+		constUnions: true,
+		format:      formatZod,
+	})
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+import { z } from 'zod';
+
+export const PositionSchema = z.object({
+	X: z.number(),
+	Y: z.number(),
+});
+export type Position = z.infer<typeof PositionSchema>;
+
+export const directionSchema = z.enum(['up', 'down']);
+export type direction = z.infer<typeof directionSchema>;
+
+export const speedSchema = z.union([z.literal(1), z.literal(2)]);
+export type speed = z.infer<typeof speedSchema>;
+
+export interface Turtle {
+	Coordinates: Position;
+	Direction: direction;
+	Speed: speed;
+	Born: string;
+	Tags?: string[] | null;
+	Parent: Turtle | null;
+}
+export const TurtleSchema: z.ZodType<Turtle> = z.object({
+	Coordinates: PositionSchema,
+	Direction: directionSchema,
+	Speed: speedSchema,
+	Born: z.string(),
+	Tags: z.array(z.string()).nullable().optional(),
+	Parent: z.lazy(() => TurtleSchema).nullable(),
+});
+
+export const PondSchema = z.object({
+	Turtles: z.record(z.string(), TurtleSchema).nullable(),
+});
+export type Pond = z.infer<typeof PondSchema>;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestGenerate_JSONSchemaFormat_Success(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{
+		typeNames:   []string{"Turtle"},
+		constUnions: true,
+		docComments: true,
+		format:      formatJSONSchema,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, b.String(), `
+		"speed": {
+			"description": "speed is an iota-based enum.",
+			"enum": [
+				1,
+				2
+			]
+		},`)
+	assert.Contains(t, b.String(), `
+				"Born": {
+					"type": "string",
+					"description": "Born is when the turtle hatched."
+				},`)
+}
+
+func TestGenerate_OutDir_WritesModules(t *testing.T) {
+	dir := t.TempDir()
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{
+		typeNames: []string{"Pond"},
+		namespace: "water",
+		ignoreNil: true,
+		outDir:    dir,
+		groupBy:   groupByNamespace,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, b.String())
+	contents, err := os.ReadFile(filepath.Join(dir, "water.ts"))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), `
+export interface Pond {
+	Turtles: { [key: string]: Turtle };
+}
+`)
+
+	err = generate(&b, io.Discard, "", []string{"./testdata/example"}, options{outDir: dir, groupBy: "file"})
+	require.EqualError(t, err, `unknown grouping "file"`)
+	err = generate(&b, io.Discard, "", []string{"./testdata/example"}, options{outDir: dir, format: formatZod})
+	require.EqualError(t, err, `format "zod" cannot be written as ES modules`)
+}
+
+func TestGenerate_SortByName_Success(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{
+		constUnions: true,
+		sort:        sortByName,
+	})
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Pond {
+	Turtles: { [key: string]: Turtle } | null;
+}
+
+export interface Position {
+	X: number;
+	Y: number;
+}
+
+export interface Turtle {
+	Coordinates: Position;
+	Direction: direction;
+	Speed: speed;
+	Born: string;
+	Tags?: string[] | null;
+	Parent: Turtle | null;
+}
+
+export type direction = 'up' | 'down';
+
+export type speed = 1 | 2;
+`
+	assert.Equal(t, expected, b.String())
+
+	err = generate(&b, io.Discard, "", []string{"./testdata/example"}, options{sort: "random"})
+	require.EqualError(t, err, `unknown sort order "random"`)
+}
+
+func TestGenerate_RenderOptions_Success(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{
+		typeNames:      []string{"position", "direction"},
+		constUnions:    true,
+		indent:         2,
+		quotes:         quotesDouble,
+		omitSemicolons: true,
+		header:         "// Copyright 2024 Example Inc.",
+		crlf:           true,
+	})
+	require.NoError(t, err)
+	expected := `// Copyright 2024 Example Inc.
+
+export interface Position {
+  X: number
+  Y: number
+}
+
+export type direction = "up" | "down"
+`
+	assert.Equal(t, strings.ReplaceAll(expected, "\n", "\r\n"), b.String())
+
+	err = generate(&b, io.Discard, "", []string{"./testdata/example"}, options{quotes: "backticks"})
+	require.EqualError(t, err, `unknown quote style "backticks"`)
+
+	err = generate(&b, io.Discard, "", []string{"./testdata/example"}, options{indent: -1})
+	require.EqualError(t, err, `invalid indentation -1`)
+}
+
+func TestCheckOutput_StaleFile_ListsDifferences(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{typeNames: []string{"position"}})
+	require.NoError(t, err)
+
+	filename := filepath.Join(t.TempDir(), "position.ts")
+	require.NoError(t, os.WriteFile(filename, b.Bytes(), 0644))
+	require.NoError(t, checkOutput(filename, b.Bytes()))
+
+	stale := strings.Replace(b.String(), "\tY: number;\n", "", 1)
+	require.NoError(t, os.WriteFile(filename, []byte(stale), 0644))
+	err = checkOutput(filename, b.Bytes())
+	require.EqualError(t, err, filename+` is out of date:
+changed: Position
+--- existing
++++ generated
+@@ -2,4 +2,5 @@
+ 
+ export interface Position {
+ 	X: number;
++	Y: number;
+ }`)
+
+	err = checkOutput(filepath.Join(t.TempDir(), "missing.ts"), b.Bytes())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "added: Position\n")
+}
+
+func TestGenerate_ASTFormat_LoadsBack(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{
+		typeNames:   []string{"Turtle"},
+		constUnions: true,
+		format:      formatAST,
+	})
+	require.NoError(t, err)
+
+	typeDeclarations, err := typescript.UnmarshalTypeDeclarations(b.Bytes())
+	require.NoError(t, err)
+	var qualifiedNames []string
+	for _, typeDeclaration := range typeDeclarations {
+		qualifiedNames = append(qualifiedNames, typeDeclaration.QualifiedName())
+	}
+	assert.Equal(t, []string{"Position", "direction", "speed", "Turtle"}, qualifiedNames)
+}
+
+func TestGenerate_UnknownFormat_Error(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{format: "cobol"})
+	require.EqualError(t, err, `unknown format "cobol"`)
+}
+
+func TestGenerate_TypeNotFound_Error(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/example"}, options{typeNames: []string{"Ocean"}})
+	require.EqualError(t, err, `type "Ocean" not found`)
+}
+
+func TestGenerate_PackageNotFound_Error(t *testing.T) {
+	var b bytes.Buffer
+	err := generate(&b, io.Discard, "", []string{"./testdata/nonexistent"}, options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load packages")
+}
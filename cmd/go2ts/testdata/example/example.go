@@ -0,0 +1,72 @@
+package example
+
+import "time"
+
+type direction string
+
+const (
+	up   direction = "up"
+	down direction = "down"
+)
+
+// speed is an iota-based enum.
+type speed int
+
+const (
+	_ speed = iota
+	slow
+	fast
+	defaultSpeed = slow
+)
+
+type position struct {
+	X int
+	Y int
+}
+
+// Turtle is exported via the go2ts:export marker.
+//
+//go2ts:export
+type Turtle struct {
+	Position  position `json:"Coordinates"`
+	Direction direction
+	Speed     speed
+
+	// Born is when the turtle hatched.
+	Born   time.Time
+	Tags   []string `json:",omitempty"` // Tags are free-form labels.
+	Parent *Turtle
+	secret string
+}
+
+type (
+	// Pond is exported via the go2ts:export marker in a grouped declaration.
+	//
+	//go2ts:export
+	Pond struct {
+		Turtles map[string]Turtle
+	}
+
+	// Lake is only exported when selected by name.
+	Lake struct {
+		Ponds []Pond
+		Depth int `json:"depth,omitempy"`
+	}
+)
+
+// Shore is only exported when selected by name.
+type Shore struct {
+	Start   [2]float64
+	Samples [64]float64
+}
+
+// page is a generic type.
+type page[T any] struct {
+	Items []T
+	Total int
+}
+
+// Sea is only exported when selected by name.
+type Sea struct {
+	Lakes page[Lake]
+}
@@ -0,0 +1,249 @@
+package go2ts
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/skia-dev/go2ts/typescript"
+)
+
+// NameCollisionStrategy determines how Go2TS resolves collisions between the TypeScript names of
+// different Go types, e.g. between foo.Config and bar.Config, which would both be declared as
+// "Config" in the same TypeScript namespace.
+type NameCollisionStrategy int
+
+const (
+	// PrefixPackageName renames colliding types by prefixing their identifiers with the name of their
+	// Go package, e.g. foo.Config and bar.Config are declared as "Config" and "BarConfig",
+	// respectively. If the prefixed identifier also collides (e.g. both types are declared in
+	// packages with the same name), a numeric suffix is added, e.g. "BarConfig2".
+	//
+	// This is the default strategy.
+	PrefixPackageName NameCollisionStrategy = iota
+
+	// FailOnNameCollision reports colliding types as errors.
+	FailOnNameCollision
+
+	// CallNameCollisionFunc renames colliding types via the function supplied to
+	// SetNameCollisionFunc().
+	CallNameCollisionFunc
+)
+
+// NameCollisionFunc returns the TypeScript identifier for a Go type whose identifier is already
+// used by a different Go type in the same TypeScript namespace. The Go type is described by the
+// import path of its package (which is empty for unnamed types) and the identifier it would have
+// been declared with, e.g. "github.com/example/bar" and "Config".
+type NameCollisionFunc func(pkgPath, identifier string) string
+
+// SetNameCollisionStrategy sets the strategy used to resolve collisions between the TypeScript
+// names of different Go types. Defaults to PrefixPackageName.
+//
+// Collisions are detected as types are added, and apply to interfaces, type aliases, union types
+// and enums alike. Only the type declared second is affected, e.g. if foo.Config is added before
+// bar.Config, foo.Config keeps the "Config" identifier. If a sort order is set (see
+// SetSortOrder()), collisions resolved by PrefixPackageName don't depend on the order in which types
+// are added: the type whose package import path sorts first keeps the identifier.
+func (g *Go2TS) SetNameCollisionStrategy(strategy NameCollisionStrategy) {
+	g.nameCollisionStrategy = strategy
+}
+
+// SetNameCollisionFunc makes Go2TS resolve collisions between the TypeScript names of different Go
+// types by calling the given function, which must return a unique identifier. Any identifier
+// returned by the function that also collides is reported as an error.
+func (g *Go2TS) SetNameCollisionFunc(f NameCollisionFunc) {
+	g.nameCollisionStrategy = CallNameCollisionFunc
+	g.nameCollisionFunc = f
+}
+
+// declareIdentifier reserves a TypeScript identifier in the given namespace for the given Go type,
+// found at the given path, and returns it. This is the given identifier, unless it is already used
+// by a different Go type, in which case the collision is resolved as per the name collision
+// strategy. See SetNameCollisionStrategy().
+func (g *Go2TS) declareIdentifier(typ goType, identifier, namespace, path string) string {
+	g.requestedIdentifiers[typ.id()] = identifier
+	g.nameCollisionsResolved = false
+	if !g.isIdentifierTaken(typ, identifier, namespace) {
+		g.reserveIdentifier(typ, identifier, namespace)
+		return identifier
+	}
+	existingType := g.identifiers[qualifiedName(namespace, identifier)]
+
+	var newIdentifier string
+	switch g.nameCollisionStrategy {
+	case PrefixPackageName:
+		newIdentifier = packageNamePrefix(typ.PkgPath()) + identifier
+		for i := 2; g.isIdentifierTaken(typ, newIdentifier, namespace); i++ {
+			newIdentifier = fmt.Sprintf("%s%s%d", packageNamePrefix(typ.PkgPath()), identifier, i)
+		}
+	case CallNameCollisionFunc:
+		newIdentifier = g.nameCollisionFunc(typ.PkgPath(), identifier)
+		if g.isIdentifierTaken(typ, newIdentifier, namespace) {
+			g.fail(typ, path, fmt.Sprintf("TypeScript type %q returned by the name collision function for Go type %v is already used by Go type %v.", qualifiedName(namespace, newIdentifier), typ, g.identifiers[qualifiedName(namespace, newIdentifier)]))
+			return newIdentifier
+		}
+	default:
+		g.fail(typ, path, fmt.Sprintf("TypeScript type %q for Go type %v is already used by Go type %v.", qualifiedName(namespace, identifier), typ, existingType))
+		return identifier
+	}
+
+	g.reserveIdentifier(typ, newIdentifier, namespace)
+	return newIdentifier
+}
+
+// resolveNameCollisionsInSortOrder renames the type declarations whose identifiers depend on the
+// order in which their Go types were added, if a sort order is set and the name collision strategy
+// is PrefixPackageName. See SetSortOrder().
+//
+// Anonymous structs are numbered in the order of their Go type names, e.g. "struct { A int }",
+// rather than in the order they were discovered. Of the Go types that requested the same identifier
+// in the same namespace, the one whose package import path sorts first keeps said identifier, and
+// the others are renamed as per PrefixPackageName in order of import path, and then of Go type
+// name, e.g. "main.Box[a.Config]" before "main.Box[b.Config]".
+//
+// Type declarations are only renamed once after any types are added, such that reading them has no
+// side effects otherwise.
+func (g *Go2TS) resolveNameCollisionsInSortOrder() {
+	if g.nameCollisionsResolved || g.sortOrder == AddedOrder || g.nameCollisionStrategy != PrefixPackageName {
+		return
+	}
+	defer func() { g.nameCollisionsResolved = true }()
+
+	anonymousTypes := append([]goType{}, g.anonymousTypes...)
+	sort.SliceStable(anonymousTypes, func(i, j int) bool {
+		return anonymousTypes[i].String() < anonymousTypes[j].String()
+	})
+	for i, typ := range anonymousTypes {
+		g.requestedIdentifiers[typ.id()] = fmt.Sprintf("Anonymous%d", i+1)
+	}
+
+	type candidate struct {
+		typ             goType
+		typeDeclaration typescript.TypeDeclaration
+		namespace       string
+		identifier      string
+	}
+	var candidates []candidate
+	for _, typeDeclaration := range g.typeDeclarationsInOrder {
+		typ, ok := g.identifiers[typeDeclaration.QualifiedName()]
+		if !ok {
+			continue
+		}
+		namespace := ""
+		if i := strings.LastIndex(typeDeclaration.QualifiedName(), "."); i >= 0 {
+			namespace = typeDeclaration.QualifiedName()[:i]
+		}
+		candidates = append(candidates, candidate{
+			typ:             typ,
+			typeDeclaration: typeDeclaration,
+			namespace:       namespace,
+			identifier:      g.requestedIdentifiers[typ.id()],
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].namespace != candidates[j].namespace {
+			return candidates[i].namespace < candidates[j].namespace
+		}
+		if candidates[i].identifier != candidates[j].identifier {
+			return candidates[i].identifier < candidates[j].identifier
+		}
+		if candidates[i].typ.PkgPath() != candidates[j].typ.PkgPath() {
+			return candidates[i].typ.PkgPath() < candidates[j].typ.PkgPath()
+		}
+		return candidates[i].typ.String() < candidates[j].typ.String()
+	})
+
+	for _, c := range candidates {
+		g.releaseIdentifier(typeDeclarationIdentifier(c.typeDeclaration), c.namespace)
+	}
+
+	// Requested identifiers are reserved before any prefixed ones, such that e.g. a type requesting
+	// "BarConfig" isn't renamed because of bar.Config.
+	var renamed []candidate
+	for i, c := range candidates {
+		if i > 0 && candidates[i-1].namespace == c.namespace && candidates[i-1].identifier == c.identifier {
+			renamed = append(renamed, c)
+			continue
+		}
+		g.reserveIdentifier(c.typ, c.identifier, c.namespace)
+		g.renameTypeDeclaration(c.typeDeclaration, c.identifier)
+	}
+	for _, c := range renamed {
+		g.renameTypeDeclaration(c.typeDeclaration, g.declareIdentifier(c.typ, c.identifier, c.namespace, ""))
+	}
+}
+
+// typeDeclarationIdentifier returns the identifier of the given type declaration, i.e. its qualified
+// name without its namespace.
+func typeDeclarationIdentifier(typeDeclaration typescript.TypeDeclaration) string {
+	qualifiedName := typeDeclaration.QualifiedName()
+	return qualifiedName[strings.LastIndex(qualifiedName, ".")+1:]
+}
+
+// renameTypeDeclaration sets the identifier of the given type declaration, and of any type aliases
+// that stand in for it.
+func (g *Go2TS) renameTypeDeclaration(typeDeclaration typescript.TypeDeclaration, identifier string) {
+	switch typeDeclaration := typeDeclaration.(type) {
+	case *typescript.InterfaceDeclaration:
+		typeDeclaration.Identifier = identifier
+	case *typescript.TypeAliasDeclaration:
+		typeDeclaration.Identifier = identifier
+	case *typescript.EnumDeclaration:
+		typeDeclaration.Identifier = identifier
+		for _, typeAliasDeclaration := range g.enumAliases[typeDeclaration] {
+			typeAliasDeclaration.Identifier = identifier
+		}
+	}
+}
+
+// isIdentifierTaken returns true if the given identifier is used by a Go type other than the given
+// one in the given namespace.
+func (g *Go2TS) isIdentifierTaken(typ goType, identifier, namespace string) bool {
+	existingType, ok := g.identifiers[qualifiedName(namespace, identifier)]
+	return ok && existingType.id() != typ.id()
+}
+
+// reserveIdentifier records that the given identifier is used by the given Go type in the given
+// namespace.
+func (g *Go2TS) reserveIdentifier(typ goType, identifier, namespace string) {
+	g.identifiers[qualifiedName(namespace, identifier)] = typ
+}
+
+// releaseIdentifier frees the given identifier in the given namespace, e.g. after renaming the type
+// declaration that used it.
+func (g *Go2TS) releaseIdentifier(identifier, namespace string) {
+	delete(g.identifiers, qualifiedName(namespace, identifier))
+}
+
+// qualifiedName returns the qualified name of the TypeScript type with the given namespace and
+// identifier, e.g. "MyNamespace.MyType".
+func qualifiedName(namespace, identifier string) string {
+	if namespace == "" {
+		return identifier
+	}
+	return namespace + "." + identifier
+}
+
+// packageNamePrefix returns the name of the Go package with the given import path as an identifier
+// prefix, e.g. "Bar" for "github.com/example/bar", or the empty string if the import path is empty.
+func packageNamePrefix(pkgPath string) string {
+	if pkgPath == "" {
+		return ""
+	}
+	name := path.Base(pkgPath)
+	// Package names cannot contain dashes or dots, but import paths can, e.g. "gopkg.in/yaml.v3".
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return -1
+	}, name)
+	if name == "" {
+		return ""
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
@@ -0,0 +1,563 @@
+// Package compat compares two versions of a set of TypeScript type declarations, e.g. those
+// produced by Go2TS before and after a change to a Go struct (see go2ts.Go2TS.TypeDeclarations()),
+// and classifies each change as breaking or non-breaking for the consumers of the TypeScript types.
+//
+// Consumers are either readers, which receive values of the types (e.g. a frontend parsing API
+// responses), or writers, which produce values of the types (e.g. a frontend sending API requests).
+// A change breaks readers if values of the new type may not be values of the old type, e.g. a
+// required property is removed or a union type gains a member. A change breaks writers if values of
+// the old type may not be values of the new type, e.g. a required property is added or a union type
+// loses a member. For example:
+//
+//	changes := compat.Compare(before.TypeDeclarations(), after.TypeDeclarations())
+//	for _, change := range changes {
+//		if change.Breaking() {
+//			fmt.Println(change) // e.g. "Job.Status: type narrowed from string to Status (breaks writers)"
+//		}
+//	}
+//
+// Type declarations are matched by qualified name. References to the same type declaration are
+// considered compatible, since any changes to said declaration are reported separately.
+package compat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/skia-dev/go2ts/typescript"
+)
+
+// ChangeKind is the kind of a Change.
+type ChangeKind int
+
+const (
+	// DeclarationAdded means a type declaration was added.
+	DeclarationAdded ChangeKind = iota
+
+	// DeclarationRemoved means a type declaration was removed.
+	DeclarationRemoved
+
+	// DeclarationKindChanged means a type declaration was replaced by a different kind of type
+	// declaration, e.g. a type alias by an enum, or an enum by a const object (see
+	// typescript.EnumStyle).
+	DeclarationKindChanged
+
+	// TypeParametersChanged means the type parameters of a generic type declaration changed.
+	TypeParametersChanged
+
+	// PropertyAdded means an interface property was added.
+	PropertyAdded
+
+	// PropertyRemoved means an interface property was removed.
+	PropertyRemoved
+
+	// PropertyMadeOptional means a required interface property was made optional.
+	PropertyMadeOptional
+
+	// PropertyMadeRequired means an optional interface property was made required.
+	PropertyMadeRequired
+
+	// TypeNarrowed means a type now accepts a subset of the values it accepted before, e.g. a union
+	// type lost a member.
+	TypeNarrowed
+
+	// TypeWidened means a type now accepts a superset of the values it accepted before, e.g. a union
+	// type gained a member.
+	TypeWidened
+
+	// TypeChanged means a type changed such that neither the old nor the new type accepts all the
+	// values of the other, e.g. from string to number.
+	TypeChanged
+
+	// EnumMemberAdded means an enum member was added.
+	EnumMemberAdded
+
+	// EnumMemberRemoved means an enum member was removed.
+	EnumMemberRemoved
+
+	// EnumMemberChanged means the value of an enum member changed.
+	EnumMemberChanged
+)
+
+// String implements the fmt.Stringer interface.
+func (k ChangeKind) String() string {
+	switch k {
+	case DeclarationAdded:
+		return "declaration added"
+	case DeclarationRemoved:
+		return "declaration removed"
+	case DeclarationKindChanged:
+		return "declaration kind changed"
+	case TypeParametersChanged:
+		return "type parameters changed"
+	case PropertyAdded:
+		return "property added"
+	case PropertyRemoved:
+		return "property removed"
+	case PropertyMadeOptional:
+		return "property made optional"
+	case PropertyMadeRequired:
+		return "property made required"
+	case TypeNarrowed:
+		return "type narrowed"
+	case TypeWidened:
+		return "type widened"
+	case TypeChanged:
+		return "type changed"
+	case EnumMemberAdded:
+		return "enum member added"
+	case EnumMemberRemoved:
+		return "enum member removed"
+	case EnumMemberChanged:
+		return "enum member changed"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change is a difference between two versions of a type declaration.
+type Change struct {
+	// Path is the qualified name of the changed type declaration, followed by the identifier of the
+	// changed property or enum member, if any, e.g. "api.Job" or "api.Job.Status".
+	Path string
+
+	// Kind is the kind of change.
+	Kind ChangeKind
+
+	// Old and New are the TypeScript code of the changed type or enum member value before and after
+	// the change, e.g. "string" and "'done' | 'failed'", or the kinds of type declarations for
+	// DeclarationKindChanged, e.g. "type alias" and "enum". Either may be empty, e.g. for added or
+	// removed declarations.
+	Old, New string
+
+	// BreaksReaders is true if the change breaks consumers that receive values of the type.
+	BreaksReaders bool
+
+	// BreaksWriters is true if the change breaks consumers that produce values of the type.
+	BreaksWriters bool
+}
+
+// Breaking returns true if the change breaks any consumers, i.e. readers or writers.
+func (c Change) Breaking() bool {
+	return c.BreaksReaders || c.BreaksWriters
+}
+
+// String implements the fmt.Stringer interface, e.g.
+// "Job.Status: type narrowed from string to Status (breaks writers)".
+func (c Change) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s", c.Path, c.Kind))
+	if c.Old != "" && c.New != "" {
+		sb.WriteString(fmt.Sprintf(" from %s to %s", c.Old, c.New))
+	}
+	switch {
+	case c.BreaksReaders && c.BreaksWriters:
+		sb.WriteString(" (breaks readers and writers)")
+	case c.BreaksReaders:
+		sb.WriteString(" (breaks readers)")
+	case c.BreaksWriters:
+		sb.WriteString(" (breaks writers)")
+	}
+	return sb.String()
+}
+
+// Compare returns the changes from the old type declarations to the new ones. Changes to the old
+// type declarations are returned first, in order, followed by any added type declarations.
+//
+// Changes that don't affect the values accepted by a type, e.g. reordering the members of a union
+// type or editing doc comments, are not reported.
+func Compare(oldTypeDeclarations, newTypeDeclarations []typescript.TypeDeclaration) []Change {
+	newByName := map[string]typescript.TypeDeclaration{}
+	for _, typeDeclaration := range newTypeDeclarations {
+		newByName[typeDeclaration.QualifiedName()] = typeDeclaration
+	}
+
+	var changes []Change
+	oldByName := map[string]bool{}
+	for _, oldTypeDeclaration := range oldTypeDeclarations {
+		qualifiedName := oldTypeDeclaration.QualifiedName()
+		oldByName[qualifiedName] = true
+		newTypeDeclaration, ok := newByName[qualifiedName]
+		if !ok {
+			changes = append(changes, Change{
+				Path:          qualifiedName,
+				Kind:          DeclarationRemoved,
+				BreaksReaders: true,
+				BreaksWriters: true,
+			})
+			continue
+		}
+		changes = append(changes, compareTypeDeclarations(oldTypeDeclaration, newTypeDeclaration)...)
+	}
+
+	for _, newTypeDeclaration := range newTypeDeclarations {
+		if !oldByName[newTypeDeclaration.QualifiedName()] {
+			changes = append(changes, Change{
+				Path: newTypeDeclaration.QualifiedName(),
+				Kind: DeclarationAdded,
+			})
+		}
+	}
+	return changes
+}
+
+// compareTypeDeclarations returns the changes between two versions of a type declaration.
+func compareTypeDeclarations(oldTypeDeclaration, newTypeDeclaration typescript.TypeDeclaration) []Change {
+	path := oldTypeDeclaration.QualifiedName()
+
+	oldKind, newKind := typeDeclarationKind(oldTypeDeclaration), typeDeclarationKind(newTypeDeclaration)
+	if oldKind != newKind {
+		return []Change{{
+			Path:          path,
+			Kind:          DeclarationKindChanged,
+			Old:           oldKind,
+			New:           newKind,
+			BreaksReaders: true,
+			BreaksWriters: true,
+		}}
+	}
+
+	oldTypeParameters, newTypeParameters := typescript.TypeParameters(oldTypeDeclaration), typescript.TypeParameters(newTypeDeclaration)
+	if typeParameterList(oldTypeParameters) != typeParameterList(newTypeParameters) {
+		return []Change{{
+			Path:          path,
+			Kind:          TypeParametersChanged,
+			Old:           typeParameterList(oldTypeParameters),
+			New:           typeParameterList(newTypeParameters),
+			BreaksReaders: true,
+			BreaksWriters: true,
+		}}
+	}
+
+	switch oldTypeDeclaration := oldTypeDeclaration.(type) {
+	case *typescript.InterfaceDeclaration:
+		return compareInterfaceDeclarations(oldTypeDeclaration, newTypeDeclaration.(*typescript.InterfaceDeclaration))
+	case *typescript.TypeAliasDeclaration:
+		if change, ok := compareTypes(path, oldTypeDeclaration.Type, newTypeDeclaration.(*typescript.TypeAliasDeclaration).Type); ok {
+			return []Change{change}
+		}
+		return nil
+	case *typescript.EnumDeclaration:
+		return compareEnumDeclarations(oldTypeDeclaration, newTypeDeclaration.(*typescript.EnumDeclaration))
+	}
+	panic(fmt.Sprintf("Unknown TypeScript type declaration: %T.", oldTypeDeclaration))
+}
+
+// compareInterfaceDeclarations returns the changes between two versions of an interface
+// declaration, i.e. the changes to its properties, matched by identifier.
+func compareInterfaceDeclarations(oldInterface, newInterface *typescript.InterfaceDeclaration) []Change {
+	path := oldInterface.QualifiedName()
+	newProperties := map[string]typescript.PropertySignature{}
+	for _, prop := range newInterface.Properties {
+		newProperties[prop.Identifier] = prop
+	}
+
+	var changes []Change
+	oldProperties := map[string]bool{}
+	for _, oldProp := range oldInterface.Properties {
+		oldProperties[oldProp.Identifier] = true
+		propPath := path + "." + oldProp.Identifier
+		newProp, ok := newProperties[oldProp.Identifier]
+		if !ok {
+			// Readers may rely on required properties. Writers may keep writing the property, which is
+			// ignored.
+			changes = append(changes, Change{
+				Path:          propPath,
+				Kind:          PropertyRemoved,
+				Old:           oldProp.Type.ToTypeScript(),
+				BreaksReaders: !oldProp.Optional,
+			})
+			continue
+		}
+
+		if oldProp.Optional && !newProp.Optional {
+			changes = append(changes, Change{
+				Path:          propPath,
+				Kind:          PropertyMadeRequired,
+				BreaksWriters: true,
+			})
+		} else if !oldProp.Optional && newProp.Optional {
+			changes = append(changes, Change{
+				Path:          propPath,
+				Kind:          PropertyMadeOptional,
+				BreaksReaders: true,
+			})
+		}
+		if change, ok := compareTypes(propPath, oldProp.Type, newProp.Type); ok {
+			changes = append(changes, change)
+		}
+	}
+
+	for _, newProp := range newInterface.Properties {
+		if !oldProperties[newProp.Identifier] {
+			// Readers ignore unknown properties, but writers must write required properties.
+			changes = append(changes, Change{
+				Path:          path + "." + newProp.Identifier,
+				Kind:          PropertyAdded,
+				New:           newProp.Type.ToTypeScript(),
+				BreaksWriters: !newProp.Optional,
+			})
+		}
+	}
+	return changes
+}
+
+// compareEnumDeclarations returns the changes between two versions of an enum declaration, i.e. the
+// changes to its members, matched by identifier. Enum members are referenced by identifier, thus
+// removing or changing a member breaks both readers and writers.
+func compareEnumDeclarations(oldEnum, newEnum *typescript.EnumDeclaration) []Change {
+	path := oldEnum.QualifiedName()
+	newMembers := map[string]typescript.EnumMember{}
+	for _, member := range newEnum.Members {
+		newMembers[member.Identifier] = member
+	}
+
+	var changes []Change
+	oldMembers := map[string]bool{}
+	for _, oldMember := range oldEnum.Members {
+		oldMembers[oldMember.Identifier] = true
+		newMember, ok := newMembers[oldMember.Identifier]
+		if !ok {
+			changes = append(changes, Change{
+				Path:          path + "." + oldMember.Identifier,
+				Kind:          EnumMemberRemoved,
+				Old:           oldMember.Value.ToTypeScript(),
+				BreaksReaders: true,
+				BreaksWriters: true,
+			})
+		} else if *oldMember.Value != *newMember.Value {
+			changes = append(changes, Change{
+				Path:          path + "." + oldMember.Identifier,
+				Kind:          EnumMemberChanged,
+				Old:           oldMember.Value.ToTypeScript(),
+				New:           newMember.Value.ToTypeScript(),
+				BreaksReaders: true,
+				BreaksWriters: true,
+			})
+		}
+	}
+
+	for _, newMember := range newEnum.Members {
+		if !oldMembers[newMember.Identifier] {
+			// Readers may not handle the new value, e.g. in exhaustive switch statements.
+			changes = append(changes, Change{
+				Path:          path + "." + newMember.Identifier,
+				Kind:          EnumMemberAdded,
+				New:           newMember.Value.ToTypeScript(),
+				BreaksReaders: true,
+			})
+		}
+	}
+	return changes
+}
+
+// compareTypes returns the change between two versions of the type at the given path and true, or
+// false if they accept the same values.
+func compareTypes(path string, oldType, newType typescript.Type) (Change, bool) {
+	c := &comparer{assumed: map[[2]string]bool{}}
+	// Readers expect values of the old type, and writers produce values of the old type.
+	readersOK := c.assignable(newType, oldType)
+	writersOK := c.assignable(oldType, newType)
+	change := Change{
+		Path:          path,
+		Old:           oldType.ToTypeScript(),
+		New:           newType.ToTypeScript(),
+		BreaksReaders: !readersOK,
+		BreaksWriters: !writersOK,
+	}
+	switch {
+	case readersOK && writersOK:
+		return Change{}, false
+	case readersOK:
+		change.Kind = TypeNarrowed
+	case writersOK:
+		change.Kind = TypeWidened
+	default:
+		change.Kind = TypeChanged
+	}
+	return change, true
+}
+
+// comparer determines whether values of one type are values of another type.
+type comparer struct {
+	// assumed holds the pairs of qualified names of the type aliases whose assignability is being
+	// determined, which are assumed to be assignable, such that recursive type aliases terminate.
+	assumed map[[2]string]bool
+}
+
+// assignable returns true if all values of the from type are values of the to type. It errs on the
+// side of returning false, e.g. interfaces with different qualified names are never assignable.
+func (c *comparer) assignable(from, to typescript.Type) bool {
+	// All values are values of any, but any also accepts values that other types don't.
+	if to == typescript.Any {
+		return true
+	}
+
+	// References to the same type declaration are compatible, and any changes to said declaration are
+	// reported separately. References to different type aliases are compared by their types.
+	fromDeclaration, toDeclaration := referencedTypeAlias(from), referencedTypeAlias(to)
+	if fromReference, ok := from.(*typescript.TypeReference); ok {
+		if toReference, ok := to.(*typescript.TypeReference); ok && fromReference.TypeDeclaration().QualifiedName() == toReference.TypeDeclaration().QualifiedName() {
+			return true
+		}
+	}
+	if fromDeclaration != nil || toDeclaration != nil {
+		key := [2]string{qualifiedName(fromDeclaration), qualifiedName(toDeclaration)}
+		if c.assumed[key] {
+			return true
+		}
+		c.assumed[key] = true
+		defer delete(c.assumed, key)
+		if fromDeclaration != nil {
+			return c.assignable(fromDeclaration.Type, to)
+		}
+		return c.assignable(from, toDeclaration.Type)
+	}
+
+	if fromTypes, ok := unionTypes(from); ok {
+		for _, t := range fromTypes {
+			if !c.assignable(t, to) {
+				return false
+			}
+		}
+		return true
+	}
+	if toTypes, ok := unionTypes(to); ok {
+		for _, t := range toTypes {
+			if c.assignable(from, t) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch from := from.(type) {
+	case typescript.BasicType:
+		return from == to
+	case *typescript.LiteralType:
+		switch to := to.(type) {
+		case typescript.BasicType:
+			return from.BasicType == to
+		case *typescript.LiteralType:
+			return *from == *to
+		}
+		return false
+	case *typescript.ArrayType:
+		// Readonly arrays are not assignable to mutable ones.
+		to, ok := to.(*typescript.ArrayType)
+		return ok && (!from.Readonly || to.Readonly) && c.assignable(from.ItemsType, to.ItemsType)
+	case *typescript.TupleType:
+		switch to := to.(type) {
+		case *typescript.ArrayType:
+			// Tuples are assignable to arrays of any of their element types.
+			if from.Readonly && !to.Readonly {
+				return false
+			}
+			for _, elementType := range from.ElementTypes {
+				if !c.assignable(elementType, to.ItemsType) {
+					return false
+				}
+			}
+			return true
+		case *typescript.TupleType:
+			if (from.Readonly && !to.Readonly) || len(from.ElementTypes) != len(to.ElementTypes) {
+				return false
+			}
+			for i := range from.ElementTypes {
+				if !c.assignable(from.ElementTypes[i], to.ElementTypes[i]) {
+					return false
+				}
+			}
+			return true
+		}
+		return false
+	case *typescript.MapType:
+		to, ok := to.(*typescript.MapType)
+		return ok && (!from.Readonly || to.Readonly) && c.assignable(from.IndexType, to.IndexType) && c.assignable(to.IndexType, from.IndexType) && c.assignable(from.ValueType, to.ValueType)
+	case *typescript.TypeReference:
+		// References to the same type declaration were handled above.
+		return false
+	case *typescript.TypeParameter:
+		to, ok := to.(*typescript.TypeParameter)
+		return ok && from.Identifier == to.Identifier
+	case *typescript.GenericTypeReference:
+		to, ok := to.(*typescript.GenericTypeReference)
+		if !ok || from.TypeReference.TypeDeclaration().QualifiedName() != to.TypeReference.TypeDeclaration().QualifiedName() || len(from.TypeArguments) != len(to.TypeArguments) {
+			return false
+		}
+		for i := range from.TypeArguments {
+			if !c.assignable(from.TypeArguments[i], to.TypeArguments[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	panic(fmt.Sprintf("Unknown TypeScript type: %T.", from))
+}
+
+// referencedTypeAlias returns the non-generic type alias declaration referenced by the given type,
+// or nil if the type is not a reference to one.
+func referencedTypeAlias(t typescript.Type) *typescript.TypeAliasDeclaration {
+	reference, ok := t.(*typescript.TypeReference)
+	if !ok {
+		return nil
+	}
+	alias, ok := reference.TypeDeclaration().(*typescript.TypeAliasDeclaration)
+	if !ok || len(alias.TypeParameters) > 0 {
+		return nil
+	}
+	return alias
+}
+
+// qualifiedName returns the qualified name of the given type alias declaration, or the empty string
+// if it's nil.
+func qualifiedName(alias *typescript.TypeAliasDeclaration) string {
+	if alias == nil {
+		return ""
+	}
+	return alias.QualifiedName()
+}
+
+// unionTypes returns the members of the given type and true if it's a union type, or false
+// otherwise.
+func unionTypes(t typescript.Type) ([]typescript.Type, bool) {
+	switch t := t.(type) {
+	case typescript.UnionType:
+		return t.Types, true
+	case *typescript.UnionType:
+		return t.Types, true
+	}
+	return nil, false
+}
+
+// typeDeclarationKind returns the kind of the given type declaration, e.g. "interface".
+//
+// Enums rendered as constant objects are a different kind of type declaration than TypeScript
+// enums, as literals such as 'up' are values of the former but not of the latter, and the members of
+// the latter can be used as types but not those of the former. See typescript.EnumStyle.
+func typeDeclarationKind(typeDeclaration typescript.TypeDeclaration) string {
+	switch typeDeclaration := typeDeclaration.(type) {
+	case *typescript.InterfaceDeclaration:
+		return "interface"
+	case *typescript.TypeAliasDeclaration:
+		return "type alias"
+	case *typescript.EnumDeclaration:
+		if typeDeclaration.Style == typescript.ConstObject {
+			return "const object"
+		}
+		return "enum"
+	}
+	panic(fmt.Sprintf("Unknown TypeScript type declaration: %T.", typeDeclaration))
+}
+
+// typeParameterList returns the identifiers of the given type parameters, e.g. "<K, V>", or the
+// empty string if there are none.
+func typeParameterList(typeParameters []*typescript.TypeParameter) string {
+	if len(typeParameters) == 0 {
+		return ""
+	}
+	var identifiers []string
+	for _, typeParameter := range typeParameters {
+		identifiers = append(identifiers, typeParameter.Identifier)
+	}
+	return "<" + strings.Join(identifiers, ", ") + ">"
+}
@@ -0,0 +1,225 @@
+package compat
+
+import (
+	"testing"
+
+	"github.com/skia-dev/go2ts"
+	"github.com/skia-dev/go2ts/typescript"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare_Go2TSTypes_ClassifiesChanges(t *testing.T) {
+	type Status string
+
+	before := go2ts.New()
+	{
+		type Job struct {
+			ID       string
+			Owner    string
+			Priority *int `json:",omitempty"`
+			Deadline string
+			Labels   map[string]string
+			Retries  int
+		}
+		before.Add(Job{})
+	}
+
+	after := go2ts.New()
+	{
+		type Job struct {
+			ID       string
+			Priority int
+			Deadline string `json:",omitempty"`
+			Labels   map[string]string
+			Retries  string
+			Status   Status
+			Comment  string `json:",omitempty"`
+		}
+		after.AddUnion([]Status{"done", "failed"})
+		after.Add(Job{})
+	}
+
+	changes := Compare(before.TypeDeclarations(), after.TypeDeclarations())
+	var descriptions []string
+	for _, change := range changes {
+		descriptions = append(descriptions, change.String())
+	}
+	assert.Equal(t, []string{
+		"Job.Owner: property removed (breaks readers)",
+		"Job.Priority: property made required (breaks writers)",
+		"Job.Priority: type narrowed from number | null to number (breaks writers)",
+		"Job.Deadline: property made optional (breaks readers)",
+		"Job.Retries: type changed from number to string (breaks readers and writers)",
+		"Job.Status: property added (breaks writers)",
+		"Job.Comment: property added",
+		"Status: declaration added",
+	}, descriptions)
+}
+
+func TestCompare_TypeAliasesAndEnums_ClassifiesChanges(t *testing.T) {
+	literal := func(value string) *typescript.LiteralType {
+		return &typescript.LiteralType{BasicType: typescript.String, Literal: value}
+	}
+
+	oldDirection := &typescript.TypeAliasDeclaration{
+		Identifier: "Direction",
+		Type:       &typescript.UnionType{Types: []typescript.Type{literal("up"), literal("down")}},
+	}
+	newDirection := &typescript.TypeAliasDeclaration{
+		Identifier: "Direction",
+		Type:       &typescript.UnionType{Types: []typescript.Type{literal("down"), literal("up"), literal("left")}},
+	}
+	oldName := &typescript.TypeAliasDeclaration{Identifier: "Name", Type: typescript.String}
+	newName := &typescript.TypeAliasDeclaration{Identifier: "Name", Type: &typescript.UnionType{Types: []typescript.Type{literal("Leonardo"), literal("Raphael")}}}
+	oldSpeed := &typescript.EnumDeclaration{
+		Identifier: "Speed",
+		Members: []typescript.EnumMember{
+			{Identifier: "Slow", Value: literal("slow")},
+			{Identifier: "Fast", Value: literal("fast")},
+		},
+	}
+	newSpeed := &typescript.EnumDeclaration{
+		Identifier: "Speed",
+		Members: []typescript.EnumMember{
+			{Identifier: "Slow", Value: literal("SLOW")},
+			{Identifier: "Turbo", Value: literal("turbo")},
+		},
+	}
+	oldShell := &typescript.TypeAliasDeclaration{Identifier: "Shell", Type: typescript.String}
+	newShell := &typescript.InterfaceDeclaration{Identifier: "Shell"}
+	removed := &typescript.InterfaceDeclaration{Identifier: "Pond"}
+
+	changes := Compare(
+		[]typescript.TypeDeclaration{oldDirection, oldName, oldSpeed, oldShell, removed},
+		[]typescript.TypeDeclaration{newDirection, newName, newSpeed, newShell},
+	)
+	assert.Equal(t, []Change{
+		{Path: "Direction", Kind: TypeWidened, Old: "'up' | 'down'", New: "'down' | 'up' | 'left'", BreaksReaders: true},
+		{Path: "Name", Kind: TypeNarrowed, Old: "string", New: "'Leonardo' | 'Raphael'", BreaksWriters: true},
+		{Path: "Speed.Slow", Kind: EnumMemberChanged, Old: "'slow'", New: "'SLOW'", BreaksReaders: true, BreaksWriters: true},
+		{Path: "Speed.Fast", Kind: EnumMemberRemoved, Old: "'fast'", BreaksReaders: true, BreaksWriters: true},
+		{Path: "Speed.Turbo", Kind: EnumMemberAdded, New: "'turbo'", BreaksReaders: true},
+		{Path: "Shell", Kind: DeclarationKindChanged, Old: "type alias", New: "interface", BreaksReaders: true, BreaksWriters: true},
+		{Path: "Pond", Kind: DeclarationRemoved, BreaksReaders: true, BreaksWriters: true},
+	}, changes)
+}
+
+func TestCompare_ReferencesAndRecursiveTypes_ComparedByQualifiedName(t *testing.T) {
+	// type Tree = Tree[] | null, before and after.
+	newTree := func() *typescript.TypeAliasDeclaration {
+		tree := &typescript.TypeAliasDeclaration{Identifier: "Tree"}
+		tree.Type = &typescript.UnionType{Types: []typescript.Type{&typescript.ArrayType{ItemsType: tree.TypeReference()}, typescript.Null}}
+		return tree
+	}
+	oldTree, newTreeDecl := newTree(), newTree()
+
+	// The Forest property's type changes from Tree to an equivalent inline type, and the Names
+	// property's type from string[] to an alias of string[].
+	names := &typescript.TypeAliasDeclaration{Identifier: "Names", Type: &typescript.ArrayType{ItemsType: typescript.String}}
+	oldPark := &typescript.InterfaceDeclaration{
+		Identifier: "Park",
+		Properties: []typescript.PropertySignature{
+			{Identifier: "Forest", Type: oldTree.TypeReference()},
+			{Identifier: "Names", Type: &typescript.ArrayType{ItemsType: typescript.String}},
+		},
+	}
+	newPark := &typescript.InterfaceDeclaration{
+		Identifier: "Park",
+		Properties: []typescript.PropertySignature{
+			{Identifier: "Forest", Type: &typescript.UnionType{Types: []typescript.Type{&typescript.ArrayType{ItemsType: newTreeDecl.TypeReference()}, typescript.Null}}},
+			{Identifier: "Names", Type: names.TypeReference()},
+		},
+	}
+
+	changes := Compare(
+		[]typescript.TypeDeclaration{oldTree, oldPark},
+		[]typescript.TypeDeclaration{newTreeDecl, names, newPark},
+	)
+	assert.Equal(t, []Change{
+		{Path: "Names", Kind: DeclarationAdded},
+	}, changes)
+	assert.False(t, changes[0].Breaking())
+}
+
+func TestCompare_ReadonlyArrays_MutableToReadonlyWidens(t *testing.T) {
+	tags := func(readonly bool) *typescript.InterfaceDeclaration {
+		return &typescript.InterfaceDeclaration{
+			Identifier: "Turtle",
+			Properties: []typescript.PropertySignature{
+				{Identifier: "Tags", Type: &typescript.ArrayType{ItemsType: typescript.String, Readonly: readonly}, Readonly: readonly},
+			},
+		}
+	}
+
+	changes := Compare([]typescript.TypeDeclaration{tags(false)}, []typescript.TypeDeclaration{tags(true)})
+	assert.Equal(t, []Change{
+		{Path: "Turtle.Tags", Kind: TypeWidened, Old: "string[]", New: "readonly string[]", BreaksReaders: true},
+	}, changes)
+}
+
+func TestCompare_TupleTypes_ComparedByLengthAndElements(t *testing.T) {
+	turtle := func(position, path typescript.Type) *typescript.InterfaceDeclaration {
+		return &typescript.InterfaceDeclaration{
+			Identifier: "Turtle",
+			Properties: []typescript.PropertySignature{
+				{Identifier: "Position", Type: position},
+				{Identifier: "Path", Type: path},
+			},
+		}
+	}
+	pair := &typescript.TupleType{ElementTypes: []typescript.Type{typescript.Number, typescript.Number}}
+	triple := &typescript.TupleType{ElementTypes: []typescript.Type{typescript.Number, typescript.Number, typescript.Number}}
+
+	changes := Compare(
+		[]typescript.TypeDeclaration{turtle(pair, pair)},
+		[]typescript.TypeDeclaration{turtle(triple, &typescript.ArrayType{ItemsType: typescript.Number})},
+	)
+	assert.Equal(t, []Change{
+		{Path: "Turtle.Position", Kind: TypeChanged, Old: "[number, number]", New: "[number, number, number]", BreaksReaders: true, BreaksWriters: true},
+		{Path: "Turtle.Path", Kind: TypeWidened, Old: "[number, number]", New: "number[]", BreaksReaders: true},
+	}, changes)
+}
+
+func TestCompare_AnyTypes_ToAnyWidensAndFromAnyNarrows(t *testing.T) {
+	turtle := func(name, age typescript.Type) *typescript.InterfaceDeclaration {
+		return &typescript.InterfaceDeclaration{
+			Identifier: "Turtle",
+			Properties: []typescript.PropertySignature{
+				{Identifier: "Name", Type: name},
+				{Identifier: "Age", Type: age},
+				{Identifier: "Shell", Type: typescript.Any},
+			},
+		}
+	}
+
+	changes := Compare(
+		[]typescript.TypeDeclaration{turtle(typescript.String, typescript.Any)},
+		[]typescript.TypeDeclaration{turtle(typescript.Any, typescript.Number)},
+	)
+	assert.Equal(t, []Change{
+		{Path: "Turtle.Name", Kind: TypeWidened, Old: "string", New: "any", BreaksReaders: true},
+		{Path: "Turtle.Age", Kind: TypeNarrowed, Old: "any", New: "number", BreaksWriters: true},
+	}, changes)
+}
+
+func TestCompare_EnumStyleChanged_DeclarationKindChanged(t *testing.T) {
+	direction := func(style typescript.EnumStyle) *typescript.EnumDeclaration {
+		return &typescript.EnumDeclaration{
+			Identifier: "Direction",
+			Members: []typescript.EnumMember{
+				{Identifier: "Up", Value: &typescript.LiteralType{BasicType: typescript.String, Literal: "up"}},
+			},
+			Style: style,
+		}
+	}
+
+	changes := Compare([]typescript.TypeDeclaration{direction(typescript.ConstObject)}, []typescript.TypeDeclaration{direction(typescript.EnumKeyword)})
+	assert.Equal(t, []Change{
+		{Path: "Direction", Kind: DeclarationKindChanged, Old: "const object", New: "enum", BreaksReaders: true, BreaksWriters: true},
+	}, changes)
+
+	changes = Compare([]typescript.TypeDeclaration{direction(typescript.EnumKeyword)}, []typescript.TypeDeclaration{direction(typescript.ConstObject)})
+	assert.Equal(t, []Change{
+		{Path: "Direction", Kind: DeclarationKindChanged, Old: "enum", New: "const object", BreaksReaders: true, BreaksWriters: true},
+	}, changes)
+}
@@ -0,0 +1,148 @@
+package go2ts
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/skia-dev/go2ts/typescript"
+)
+
+// customType maps the Go types that satisfy a predicate to a TypeScript type, overriding the
+// default, Kind-based mapping. The predicate is told whether values of the Go type are addressable,
+// which determines whether methods with pointer receivers apply (see implementsInterface()).
+type customType struct {
+	matches func(typ goType, addressable bool) bool
+	tsType  typescript.Type
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// defaultCustomTypes are consulted after any user-supplied custom types, in order.
+//
+// Types implementing json.Marshaler or encoding.TextMarshaler control their own JSON representation.
+// Consistent with json.Marshal, json.Marshaler takes precedence over encoding.TextMarshaler. The JSON
+// representation of json.Marshaler types is unknown, so they are represented as "any", with the
+// exception of well-known types such as time.Time.
+var defaultCustomTypes = []customType{
+	// See https://pkg.go.dev/time?tab=doc#Time.MarshalJSON.
+	{matches: anyAddressability(isType(reflectGoType{timeType})), tsType: typescript.String},
+	// Raw JSON can be anything. Listed explicitly for clarity, as it also implements json.Marshaler.
+	{matches: anyAddressability(isType(reflectGoType{rawMessageType})), tsType: typescript.Any},
+	{matches: implementsInterface(jsonMarshalerType), tsType: typescript.Any},
+	{matches: implementsInterface(textMarshalerType), tsType: typescript.String},
+}
+
+// AddCustomType maps the given Go type to the given TypeScript type, e.g. decimal.Decimal to
+// "string", or sql.NullString to "string | null".
+//
+// The value passed in can be an instance of a type, a reflect.Type, a reflect.Value, or a
+// types.Type. Go types loaded from source code (see AddWithNameToNamespace()) are matched by package
+// path and type name.
+//
+// Custom types are represented as the given TypeScript type wherever they are found, bypassing the
+// default mapping based on the Go type's Kind (e.g. structs won't be declared as interfaces). Named
+// Go types that aren't structs will still be declared as TypeScript type aliases, e.g.
+// "type Money int64" mapped to "string" will be declared as "export type Money = string".
+//
+// Custom types added later take precedence over custom types added earlier, and all custom types
+// take precedence over the default mappings (see AddWithNameToNamespace() for details).
+func (g *Go2TS) AddCustomType(v interface{}, tsType typescript.Type) {
+	g.customTypes = append(g.customTypes, customType{
+		matches: anyAddressability(isType(toGoType(v))),
+		tsType:  tsType,
+	})
+}
+
+// SetJSONMarshalerType sets the TypeScript type used to represent a Go type that implements
+// json.Marshaler, whose JSON representation Go2TS cannot infer (see AddCustomType()).
+//
+// This is equivalent to AddCustomType(), which also applies to Go types that don't implement
+// json.Marshaler.
+func (g *Go2TS) SetJSONMarshalerType(v interface{}, tsType typescript.Type) {
+	g.AddCustomType(v, tsType)
+}
+
+// AddCustomTypeFunc maps all Go types for which the given function returns true to the given
+// TypeScript type.
+//
+// The function is never called with pointer types; pointers are handled the usual way, i.e. by
+// adding "| null" to the type they point to (unless nil values are ignored). The function is only
+// called for Go types obtained via reflection, not for Go types loaded from source code.
+//
+// See AddCustomType() for more details.
+func (g *Go2TS) AddCustomTypeFunc(matches func(reflect.Type) bool, tsType typescript.Type) {
+	g.customTypes = append(g.customTypes, customType{
+		matches: func(typ goType, _ bool) bool {
+			return typ.reflectType() != nil && matches(typ.reflectType())
+		},
+		tsType: tsType,
+	})
+}
+
+// customTypeToTypeScriptType returns the TypeScript type for a Go type with a custom type mapping,
+// and true, or nil and false if the type has no custom type mapping.
+//
+// Consistent with json.Marshal, marshaler methods with pointer receivers only apply to addressable
+// values, e.g. values reached through pointers, slice elements and struct fields, but not map
+// values or values passed to the Add* methods.
+func (g *Go2TS) customTypeToTypeScriptType(typ goType, addressable bool) (typescript.Type, bool) {
+	// User-supplied custom types added later take precedence.
+	for i := len(g.customTypes) - 1; i >= 0; i-- {
+		if g.customTypes[i].matches(typ, addressable) {
+			return g.customTypes[i].tsType, true
+		}
+	}
+	for _, customType := range defaultCustomTypes {
+		if customType.matches(typ, addressable) {
+			return customType.tsType, true
+		}
+	}
+	return nil, false
+}
+
+// isCustomType returns true if the given Go type has a custom type mapping. See
+// customTypeToTypeScriptType().
+func (g *Go2TS) isCustomType(typ goType, addressable bool) bool {
+	_, ok := g.customTypeToTypeScriptType(typ, addressable)
+	return ok
+}
+
+// isType returns a predicate that matches the given Go type.
+//
+// Go types obtained via reflection are compared with the == operator. Otherwise, named Go types are
+// compared by package path and type name, which allows matching e.g. time.Time loaded from source
+// code, and unnamed Go types by their IDs.
+func isType(typ goType) func(goType) bool {
+	return func(t goType) bool {
+		if t.reflectType() != nil && typ.reflectType() != nil {
+			return t.reflectType() == typ.reflectType()
+		}
+		if t.Name() != "" {
+			return t.Name() == typ.Name() && t.PkgPath() == typ.PkgPath()
+		}
+		return t.id() == typ.id()
+	}
+}
+
+// anyAddressability returns a predicate that matches the Go types that satisfy the given predicate,
+// regardless of whether their values are addressable.
+func anyAddressability(matches func(goType) bool) func(goType, bool) bool {
+	return func(t goType, _ bool) bool {
+		return matches(t)
+	}
+}
+
+// implementsInterface returns a predicate that matches Go types that implement the given interface
+// with value receivers, or with pointer receivers if their values are addressable.
+func implementsInterface(interfaceType reflect.Type) func(goType, bool) bool {
+	return func(t goType, addressable bool) bool {
+		return t.implements(interfaceType, addressable)
+	}
+}
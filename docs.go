@@ -0,0 +1,155 @@
+package go2ts
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// EmitDocComments makes Go2TS render the doc comments of Go types and struct fields as JSDoc
+// comments on the corresponding TypeScript type declarations and interface properties.
+//
+// Doc comments are read from the source code of the package that declares each Go type. The
+// packages of Go types obtained via reflection will be loaded from source code via the package
+// loader (see SetPackageLoader()), which can be slow.
+// Go types whose source code is unavailable (e.g. types declared inside functions, or in packages
+// that cannot be loaded) are rendered without doc comments.
+//
+// Doc comments for types loaded from source code are only available if their packages were loaded
+// with their syntax trees, or are provided via UsePackage().
+func (g *Go2TS) EmitDocComments() {
+	g.emitDocComments = true
+}
+
+// UsePackage provides Go2TS with a Go package already loaded from source code, e.g. by the
+// golang.org/x/tools/go/packages package, given its type information and the syntax trees of its
+// files, which will be used instead of loading said package again whenever Go2TS needs to inspect
+// its source code (see DiscoverConstUnions() and EmitDocComments()).
+func (g *Go2TS) UsePackage(pkg *types.Package, syntax []*ast.File) {
+	g.packages[pkg.Path()] = &sourcePackage{types: pkg, syntax: syntax}
+}
+
+// docComments holds the doc comments of a Go type declaration.
+type docComments struct {
+	// typeDoc is the doc comment of the type.
+	typeDoc string
+
+	// fieldDocs maps the names of the fields of a struct type to their doc comments, or to their line
+	// comments if they don't have a doc comment.
+	fieldDocs map[string]string
+}
+
+// typeDoc returns the doc comment of the given Go type, or the empty string if there is none or
+// Go2TS isn't configured to emit doc comments. See EmitDocComments().
+func (g *Go2TS) typeDoc(typ goType) string {
+	if docs := g.lookupDocComments(typ); docs != nil {
+		return docs.typeDoc
+	}
+	return ""
+}
+
+// fieldDoc returns the doc comment of the field with the given name of the given struct type, or
+// the empty string if there is none or Go2TS isn't configured to emit doc comments. See
+// EmitDocComments().
+func (g *Go2TS) fieldDoc(structType goType, fieldName string) string {
+	if docs := g.lookupDocComments(structType); docs != nil {
+		return docs.fieldDocs[fieldName]
+	}
+	return ""
+}
+
+// lookupDocComments returns the doc comments of the given Go type, or nil if they're unavailable.
+func (g *Go2TS) lookupDocComments(typ goType) *docComments {
+	if !g.emitDocComments || typ.Name() == "" || typ.PkgPath() == "" {
+		return nil
+	}
+
+	docsByTypeName, ok := g.docComments[typ.PkgPath()]
+	if !ok {
+		// Packages are indexed at most once, regardless of whether they can be loaded.
+		pkg, err := g.loadPackage(typ.PkgPath())
+		if err == nil {
+			docsByTypeName = indexDocComments(pkg.syntax)
+		}
+		g.docComments[typ.PkgPath()] = docsByTypeName
+	}
+
+	// Go types with type parameters are named e.g. "Pair[int,string]" by reflection.
+	name := typ.Name()
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	return docsByTypeName[name]
+}
+
+// indexDocComments returns the doc comments of all the package-level type declarations in the given
+// files, indexed by type name.
+func indexDocComments(files []*ast.File) map[string]*docComments {
+	docsByTypeName := map[string]*docComments{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+
+				// The doc comment of an ungrouped type declaration (e.g. "type Foo int") is attached to
+				// the declaration rather than to the type spec.
+				doc := typeSpec.Doc
+				if doc == nil && !genDecl.Lparen.IsValid() {
+					doc = genDecl.Doc
+				}
+
+				docs := &docComments{
+					typeDoc:   commentText(doc),
+					fieldDocs: map[string]string{},
+				}
+				if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+					for _, field := range structType.Fields.List {
+						fieldDoc := commentText(field.Doc)
+						if fieldDoc == "" {
+							fieldDoc = commentText(field.Comment)
+						}
+						for _, name := range field.Names {
+							docs.fieldDocs[name.Name] = fieldDoc
+						}
+						// Embedded fields are named after their types, e.g. "*pkg.Foo[T]" is named "Foo".
+						if len(field.Names) == 0 {
+							if name := embeddedFieldName(field.Type); name != "" {
+								docs.fieldDocs[name] = fieldDoc
+							}
+						}
+					}
+				}
+				docsByTypeName[typeSpec.Name.Name] = docs
+			}
+		}
+	}
+	return docsByTypeName
+}
+
+// embeddedFieldName returns the name of an embedded field with the given type expression.
+func embeddedFieldName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.StarExpr:
+		return embeddedFieldName(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedFieldName(expr.X)
+	case *ast.IndexListExpr:
+		return embeddedFieldName(expr.X)
+	}
+	return ""
+}
+
+// commentText returns the text of the given comment group without comment markers and directives
+// (e.g. "//go2ts:export"), or the empty string if the comment group is nil.
+func commentText(commentGroup *ast.CommentGroup) string {
+	return strings.TrimSpace(commentGroup.Text())
+}
@@ -0,0 +1,163 @@
+package go2ts
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/skia-dev/go2ts/typescript"
+)
+
+// SetEnumStyle sets the style of the TypeScript enums added after calling this method. Defaults to
+// typescript.EnumKeyword.
+//
+// See typescript.EnumStyle for details.
+func (g *Go2TS) SetEnumStyle(style typescript.EnumStyle) {
+	g.enumStyle = style
+}
+
+// AddEnum adds a TypeScript definition for an enum with the values in 'v', which must be a slice or
+// an array, and the given member names.
+//
+// See AddEnumWithNameToNamespace() for more details.
+func (g *Go2TS) AddEnum(v interface{}, names []string) {
+	g.AddEnumWithNameToNamespace(v, names, "", "")
+}
+
+// AddEnumToNamespace adds a TypeScript definition for an enum with the values in 'v', which must be
+// a slice or an array, and the given member names, to the given TypeScript namespace.
+//
+// See AddEnumWithNameToNamespace() for more details.
+func (g *Go2TS) AddEnumToNamespace(v interface{}, names []string, namespace string) {
+	g.AddEnumWithNameToNamespace(v, names, "", namespace)
+}
+
+// AddEnumWithName adds a TypeScript definition for an enum with the values in 'v', which must be a
+// slice or an array, and the given member names.
+//
+// See AddEnumWithNameToNamespace() for more details.
+func (g *Go2TS) AddEnumWithName(v interface{}, names []string, typeName string) {
+	g.AddEnumWithNameToNamespace(v, names, typeName, "")
+}
+
+// AddEnumWithNameToNamespace adds a TypeScript definition for an enum with the values in 'v', which
+// must be a slice or an array, and the given member names, to the given namespace.
+//
+// This is an alternative to AddUnionWithNameToNamespace() for enum-like types whose values are
+// needed at runtime by the TypeScript code. The names are typically the names of the Go constants
+// corresponding to each value, e.g.:
+//
+//	AddEnum([]Direction{Up, Down}, []string{"Up", "Down"})
+//
+// results in "export enum Direction { Up = 'up', Down = 'down' }". Values must be numbers or
+// strings, or booleans if the enum style is typescript.ConstObject (see SetEnumStyle()). Names must
+// be unique, valid TypeScript identifiers.
+//
+// If typeName is the empty string then the name of type of elements in the slice or array is used
+// as the type name, otherwise the typeName supplied will be used as the TypeScript type name.
+//
+// If the Go type of the elements was previously declared as a TypeScript type alias (e.g. because it
+// was found in a struct field), said declaration is replaced with the enum.
+func (g *Go2TS) AddEnumWithNameToNamespace(v interface{}, names []string, typeName, namespace string) {
+	// We can only build enums from Go slices or arrays.
+	reflectType := reflect.TypeOf(v)
+	if reflectType.Kind() != reflect.Slice && reflectType.Kind() != reflect.Array {
+		g.fail(reflectGoType{reflectType}, rootPath(reflectGoType{reflectType}), fmt.Sprintf("AddEnumWithName must be supplied an array or slice, got %v: %v", reflectType.Kind(), v))
+		return
+	}
+	elemType := reflectGoType{reflectType.Elem()}
+	path := rootPath(elemType)
+
+	values := reflect.ValueOf(v)
+	if len(names) != values.Len() {
+		g.fail(elemType, path, fmt.Sprintf("AddEnumWithName must be supplied one name per value, got %d names for %d values", len(names), values.Len()))
+		return
+	}
+
+	// Make sure we have a name for the enum.
+	if typeName == "" {
+		typeName = typeIdentifier(elemType)
+	}
+
+	members := []typescript.EnumMember{}
+	seen := map[string]bool{}
+	for i := 0; i < values.Len(); i++ {
+		value := values.Index(i)
+		valuePath := fmt.Sprintf("%s[%d]", path, i)
+
+		// TypeScript enums only support numbers and strings.
+		var basicType typescript.BasicType
+		if value.Kind() == reflect.Bool && g.enumStyle == typescript.ConstObject {
+			basicType = typescript.Boolean
+		} else if isNumber(value.Kind()) {
+			basicType = typescript.Number
+		} else if value.Kind() == reflect.String {
+			basicType = typescript.String
+		} else {
+			g.fail(reflectGoType{value.Type()}, valuePath, fmt.Sprintf("Go Kind %q cannot be used in a TypeScript enum.", value.Kind()))
+			continue
+		}
+
+		if !typescript.IsIdentifier(names[i]) {
+			g.fail(reflectGoType{value.Type()}, valuePath, fmt.Sprintf("Invalid TypeScript enum member name %q.", names[i]))
+			continue
+		}
+		if seen[names[i]] {
+			g.fail(reflectGoType{value.Type()}, valuePath, fmt.Sprintf("Duplicate TypeScript enum member name %q.", names[i]))
+			continue
+		}
+		seen[names[i]] = true
+
+		members = append(members, typescript.EnumMember{
+			Identifier: names[i],
+			Value: &typescript.LiteralType{
+				BasicType: basicType,
+				Literal:   fmt.Sprintf("%v", value.Interface()),
+			},
+		})
+	}
+
+	g.addEnumDeclaration(elemType, &typescript.EnumDeclaration{
+		Namespace:  namespace,
+		Identifier: typeName,
+		Members:    members,
+		Style:      g.enumStyle,
+		Doc:        g.typeDoc(elemType),
+	}, path)
+}
+
+// addEnumDeclaration declares the given TypeScript enum for the given Go type.
+func (g *Go2TS) addEnumDeclaration(typ goType, enumDeclaration *typescript.EnumDeclaration, path string) {
+	existingTypeDeclaration, ok := g.typeDeclarations[typ.id()]
+	if !ok {
+		enumDeclaration.Identifier = g.declareIdentifier(typ, enumDeclaration.Identifier, enumDeclaration.Namespace, path)
+		g.getOrSaveTypeDeclaration(typ, enumDeclaration)
+		return
+	}
+
+	switch existingTypeDeclaration := existingTypeDeclaration.(type) {
+	case *typescript.EnumDeclaration:
+		// The Go type was already added as an enum, so we update it.
+		g.releaseIdentifier(existingTypeDeclaration.Identifier, existingTypeDeclaration.Namespace)
+		enumDeclaration.Identifier = g.declareIdentifier(typ, enumDeclaration.Identifier, enumDeclaration.Namespace, path)
+		*existingTypeDeclaration = *enumDeclaration
+	case *typescript.TypeAliasDeclaration:
+		// The Go type was already added as a type alias, which might be referenced by other type
+		// declarations. We replace the type alias with the enum in the output, and turn the type alias
+		// into an alias for the enum, which ensures any existing references remain valid.
+		g.releaseIdentifier(existingTypeDeclaration.Identifier, existingTypeDeclaration.Namespace)
+		enumDeclaration.Identifier = g.declareIdentifier(typ, enumDeclaration.Identifier, enumDeclaration.Namespace, path)
+		g.typeDeclarations[typ.id()] = enumDeclaration
+		g.packagePaths[enumDeclaration] = typ.PkgPath()
+		for i, typeDeclaration := range g.typeDeclarationsInOrder {
+			if typeDeclaration == existingTypeDeclaration {
+				g.typeDeclarationsInOrder[i] = enumDeclaration
+			}
+		}
+		existingTypeDeclaration.Namespace = enumDeclaration.Namespace
+		existingTypeDeclaration.Identifier = enumDeclaration.Identifier
+		existingTypeDeclaration.Type = enumDeclaration.TypeReference()
+		g.enumAliases[enumDeclaration] = append(g.enumAliases[enumDeclaration], existingTypeDeclaration)
+	default:
+		g.fail(typ, path, fmt.Sprintf("Go type %v was already added as something other than a TypeScript type alias or enum.", typ))
+	}
+}
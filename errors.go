@@ -0,0 +1,46 @@
+package go2ts
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Error describes a Go type that cannot be converted into a TypeScript type, or a problem with a Go
+// type that might indicate a mistake (see Go2TS.Warnings()).
+type Error struct {
+	// Type is the offending Go type, or nil if the type was loaded from source code (see
+	// Go2TS.AddWithNameToNamespace()).
+	Type reflect.Type
+
+	// Path identifies the offending Go type starting from the type passed to one of the Add* methods,
+	// e.g. "Farm.Stats.key". Struct fields are separated by dots, map keys and values are denoted by
+	// the ".key" and ".value" suffixes, slice and array items by the "[]" suffix, and the type
+	// arguments of generic types by their type parameters, e.g. "Catalog.Items<T>" (see
+	// Go2TS.EmitGenerics()).
+	Path string
+
+	// Reason is a human-readable explanation of the problem.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Reason)
+}
+
+var _ error = (*Error)(nil)
+
+// Errors is a list of errors accumulated by Go2TS in accumulated-errors mode, or a list of warnings.
+type Errors []*Error
+
+// Error implements the error interface.
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%d error(s) found:\n%s", len(e), strings.Join(messages, "\n"))
+}
+
+var _ error = Errors(nil)
@@ -0,0 +1,156 @@
+package go2ts
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/skia-dev/go2ts/typescript"
+)
+
+// EmitGenerics makes Go2TS declare generic Go types as generic TypeScript types, e.g.
+//
+//	type Page[T any] struct {
+//		Items []T
+//	}
+//
+// is declared as "export interface Page<T> { Items: T[] | null; }", and the Page[Item] Go type is
+// referenced as "Page<Item>".
+//
+// This requires the Go types' type parameters, which are only available for types loaded from
+// source code (e.g. by the go2ts command). Instantiated generic Go types obtained via reflection, as
+// well as all instantiated generic Go types if this method isn't called, are declared as regular
+// TypeScript types named after their type arguments, e.g. Page[Item] is declared as "PageItem".
+func (g *Go2TS) EmitGenerics() {
+	g.emitGenerics = true
+}
+
+// typeIdentifier returns the TypeScript identifier for the given named Go type, which is its name,
+// unless the type is an instantiated generic type. Said types are named after their type arguments,
+// e.g. "PageItem" for Page[github.com/example/pkg.Item], because brackets and import paths are not
+// valid in TypeScript identifiers.
+func typeIdentifier(typ goType) string {
+	name, typeArgs, ok := strings.Cut(typ.Name(), "[")
+	if !ok {
+		return name
+	}
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	words := strings.FieldsFunc(typeArgs, func(r rune) bool {
+		return strings.ContainsRune("[]{}(),;* ", r)
+	})
+	for _, word := range words {
+		// Drop import paths, e.g. "github.com/example/pkg.Item" becomes "Item".
+		if i := strings.LastIndex(word, "."); i >= 0 {
+			word = word[i+1:]
+		}
+		// Drop the suffixes of types declared inside functions, e.g. "Item·1" becomes "Item".
+		word, _, _ = strings.Cut(word, "·")
+		r, size := utf8.DecodeRuneInString(word)
+		if size == 0 {
+			continue
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+		sb.WriteString(word[size:])
+	}
+	return sb.String()
+}
+
+// genericOrigin returns the generic Go type of which the given Go type is an instance (e.g. Page[T]
+// for Page[Item]) and true, or nil and false if the given type isn't an instantiated generic type,
+// or Go2TS isn't configured to emit generics. See EmitGenerics().
+func (g *Go2TS) genericOrigin(typ goType) (goType, bool) {
+	named, ok := g.namedGenericType(typ)
+	if !ok || named.TypeArgs().Len() == 0 {
+		return nil, false
+	}
+	return newSourceGoType(named.Origin()), true
+}
+
+// typeParametersOf returns the TypeScript type parameters of the given generic Go type (e.g. T for
+// Page[T]), or nil if the given type isn't generic, or Go2TS isn't configured to emit generics. See
+// EmitGenerics().
+func (g *Go2TS) typeParametersOf(typ goType) []*typescript.TypeParameter {
+	named, ok := g.namedGenericType(typ)
+	if !ok || named.TypeArgs().Len() > 0 {
+		return nil
+	}
+	var typeParameters []*typescript.TypeParameter
+	for i := 0; i < named.TypeParams().Len(); i++ {
+		typeParameters = append(typeParameters, g.typeParameter(named.TypeParams().At(i)))
+	}
+	return typeParameters
+}
+
+// namedGenericType returns the given Go type as a generic or instantiated generic *types.Named and
+// true, or nil and false if it isn't one, or Go2TS isn't configured to emit generics.
+func (g *Go2TS) namedGenericType(typ goType) (*types.Named, bool) {
+	// Type parameters are only available for types loaded from source code.
+	s, ok := typ.(sourceGoType)
+	if !g.emitGenerics || !ok {
+		return nil, false
+	}
+	named, ok := s.t.(*types.Named)
+	if !ok || named.TypeParams().Len() == 0 {
+		return nil, false
+	}
+	return named, true
+}
+
+// isTypeParameter returns true if the given Go type is a type parameter, e.g. the T in Page[T], and
+// Go2TS is configured to emit generics. See EmitGenerics().
+func (g *Go2TS) isTypeParameter(typ goType) bool {
+	s, ok := typ.(sourceGoType)
+	if !g.emitGenerics || !ok {
+		return false
+	}
+	_, ok = s.t.(*types.TypeParam)
+	return ok
+}
+
+// typeParameter returns the TypeScript type parameter for the given Go type parameter. The same
+// *typescript.TypeParameter is returned for each Go type parameter, which ties the TypeScript type
+// parameters of generic type declarations to their uses within said declarations.
+func (g *Go2TS) typeParameter(typeParam *types.TypeParam) *typescript.TypeParameter {
+	if typeParameter, ok := g.typeParameters[typeParam]; ok {
+		return typeParameter
+	}
+	typeParameter := &typescript.TypeParameter{Identifier: typeParam.Obj().Name()}
+	g.typeParameters[typeParam] = typeParameter
+	return typeParameter
+}
+
+// genericTypeToTypeScriptType returns the TypeScript type of the given Go type and true if it's a
+// type parameter or an instantiated generic type, e.g. "T" or "Page<Item>", or nil and false
+// otherwise, or if Go2TS isn't configured to emit generics. See EmitGenerics().
+func (g *Go2TS) genericTypeToTypeScriptType(typ goType, namespace, path string, ignoreNilPolicy ignoreNilPolicy, addressable bool) (typescript.Type, bool) {
+	if g.isTypeParameter(typ) {
+		return g.typeParameter(typ.(sourceGoType).t.(*types.TypeParam)), true
+	}
+
+	origin, ok := g.genericOrigin(typ)
+	if !ok || g.isCustomType(typ, addressable) {
+		return nil, false
+	}
+
+	// Declare the generic type, e.g. Page<T>, and reference it with the TypeScript types of the type
+	// arguments, e.g. Page<Item>.
+	tsType := g.goTypeToTypeScriptType(origin, namespace, path, ignoreNilPolicy, implicitlyDiscovered, addressable)
+	typeReference, ok := tsType.(*typescript.TypeReference)
+	if !ok {
+		return tsType, true
+	}
+	named, _ := g.namedGenericType(typ)
+	genericTypeReference := &typescript.GenericTypeReference{TypeReference: typeReference}
+	for i := 0; i < named.TypeArgs().Len(); i++ {
+		typeArgPath := fmt.Sprintf("%s<%s>", path, named.TypeParams().At(i).Obj().Name())
+		// Type arguments are used as the types of struct fields and the like, which are assumed to be
+		// addressable.
+		typeArg := g.goTypeToTypeScriptType(newSourceGoType(named.TypeArgs().At(i)), namespace, typeArgPath, ignoreNilPolicy, implicitlyDiscovered, true)
+		genericTypeReference.TypeArguments = append(genericTypeReference.TypeArguments, typeArg)
+	}
+	return genericTypeReference, true
+}
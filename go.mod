@@ -1,5 +1,16 @@
 module github.com/skia-dev/go2ts
 
-go 1.13
+go 1.22.0
 
-require github.com/stretchr/testify v1.6.0
+require (
+	github.com/stretchr/testify v1.6.0
+	golang.org/x/tools v0.28.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/mod v0.22.0 // indirect
+	golang.org/x/sync v0.10.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
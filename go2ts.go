@@ -6,6 +6,7 @@ package go2ts
 import (
 	"fmt"
 	"go/ast"
+	"go/types"
 	"io"
 	"reflect"
 	"strings"
@@ -28,34 +29,215 @@ const (
 
 // Go2TS writes TypeScript definitions for Go types.
 type Go2TS struct {
-	// typeDeclarations maps any added reflect.Types to their corresponding TypeScript type
-	// declarations.
-	typeDeclarations map[reflect.Type]typescript.TypeDeclaration
+	// typeDeclarations maps the IDs of any added Go types (see goType) to their corresponding
+	// TypeScript type declarations.
+	typeDeclarations map[interface{}]typescript.TypeDeclaration
 
 	// typeDeclarationsInOrder holds type declarations in the order they were added, which determines
 	// the order they will appear in the output TypeScript code. This field and typeDeclarations
 	// should be kept in sync.
 	typeDeclarationsInOrder []typescript.TypeDeclaration
 
+	// mapKeyPlaceholders maps the IDs of Go map key types that weren't declared when first used as
+	// map keys to the type aliases that stand in for them until they are, if ever. See
+	// mapKeyTypeToTypeScriptType().
+	mapKeyPlaceholders map[interface{}]*typescript.TypeAliasDeclaration
+
+	// packagePaths maps type declarations to the import paths of the packages of their Go types, which
+	// are empty for unnamed types (e.g. anonymous structs). See Modules().
+	packagePaths map[typescript.TypeDeclaration]string
+
 	// anonymousCount keeps track of the number of anonymous structs we've had to name.
 	anonymousCount int
+
+	// anonymousTypes holds the anonymous structs we've had to name, in order of discovery. See
+	// resolveNameCollisionsInSortOrder().
+	anonymousTypes []goType
+
+	// accumulateErrors determines whether errors are accumulated in the errors field, or raised as
+	// panics. See AccumulateErrors().
+	accumulateErrors bool
+
+	// errors holds any errors found while adding types in accumulated-errors mode.
+	errors Errors
+
+	// warnings holds any problems found while adding types that don't prevent the types from being
+	// converted to TypeScript. See Warnings().
+	warnings Errors
+
+	// customTypes holds the user-supplied custom type mappings in the order they were added. See
+	// AddCustomType() and AddCustomTypeFunc().
+	customTypes []customType
+
+	// discoverConstUnions determines whether named Go types with constants should be declared as
+	// TypeScript union types. See DiscoverConstUnions().
+	discoverConstUnions bool
+
+	// packageLoader loads the packages of Go types obtained via reflection from source code. See
+	// SetPackageLoader().
+	packageLoader PackageLoader
+
+	// packages caches the Go packages loaded from source code by import path. A nil value means the
+	// package failed to load.
+	packages map[string]*sourcePackage
+
+	// emitDocComments determines whether the doc comments of Go types and struct fields should be
+	// rendered as JSDoc comments. See EmitDocComments().
+	emitDocComments bool
+
+	// docComments caches the doc comments of the Go types declared in each package, indexed by import
+	// path and type name. A nil value means the package's doc comments are unavailable.
+	docComments map[string]map[string]*docComments
+
+	// enumStyle is the style of the TypeScript enums added via the AddEnum* methods. See
+	// SetEnumStyle().
+	enumStyle typescript.EnumStyle
+
+	// emitTypeGuards determines whether Render should output type guard functions. See
+	// EmitTypeGuards().
+	emitTypeGuards bool
+
+	// identifiers maps the qualified names of all TypeScript type declarations to the Go types they
+	// were declared for, which is used to detect name collisions. See SetNameCollisionStrategy().
+	identifiers map[string]goType
+
+	// requestedIdentifiers maps the IDs of Go types to the identifiers they were declared with before
+	// resolving any name collisions, e.g. "Config" for a type renamed to "BarConfig". See
+	// resolveNameCollisionsInSortOrder().
+	requestedIdentifiers map[interface{}]string
+
+	// nameCollisionsResolved is true if the type declarations were renamed by
+	// resolveNameCollisionsInSortOrder() after the last type declaration was added.
+	nameCollisionsResolved bool
+
+	// enumAliases maps enums to the type aliases they replaced in the output, which alias said enums
+	// such that any existing references remain valid, and must be renamed along with them. See
+	// renameTypeDeclaration().
+	enumAliases map[*typescript.EnumDeclaration][]*typescript.TypeAliasDeclaration
+
+	// nameCollisionStrategy determines how name collisions are resolved. See
+	// SetNameCollisionStrategy().
+	nameCollisionStrategy NameCollisionStrategy
+
+	// nameCollisionFunc resolves name collisions if the strategy is CallNameCollisionFunc. See
+	// SetNameCollisionFunc().
+	nameCollisionFunc NameCollisionFunc
+
+	// emitGenerics determines whether generic Go types should be declared as generic TypeScript types.
+	// See EmitGenerics().
+	emitGenerics bool
+
+	// typeParameters maps the type parameters of generic Go types loaded from source code to their
+	// TypeScript type parameters. See EmitGenerics().
+	typeParameters map[*types.TypeParam]*typescript.TypeParameter
+
+	// sortOrder determines the order in which type declarations are output. See SetSortOrder().
+	sortOrder SortOrder
+
+	// renderOptions determines how the TypeScript code is formatted. See SetRenderOptions().
+	renderOptions typescript.RenderOptions
+
+	// emitReadonly determines whether all interface properties, arrays and maps should be declared as
+	// readonly. See EmitReadonly().
+	emitReadonly bool
+
+	// maxTupleLength is the maximum length of the Go arrays declared as TypeScript tuples. See
+	// SetMaxTupleLength().
+	maxTupleLength int
 }
 
 // New returns a new *Go2TS.
 func New() *Go2TS {
 	ret := &Go2TS{
-		typeDeclarations:        map[reflect.Type]typescript.TypeDeclaration{},
+		typeDeclarations:        map[interface{}]typescript.TypeDeclaration{},
 		typeDeclarationsInOrder: []typescript.TypeDeclaration{},
+		mapKeyPlaceholders:      map[interface{}]*typescript.TypeAliasDeclaration{},
+		packagePaths:            map[typescript.TypeDeclaration]string{},
+		packages:                map[string]*sourcePackage{},
+		docComments:             map[string]map[string]*docComments{},
+		identifiers:             map[string]goType{},
+		requestedIdentifiers:    map[interface{}]string{},
+		enumAliases:             map[*typescript.EnumDeclaration][]*typescript.TypeAliasDeclaration{},
+		typeParameters:          map[*types.TypeParam]*typescript.TypeParameter{},
 	}
 	return ret
 }
 
-func (g *Go2TS) getOrSaveTypeDeclaration(reflectType reflect.Type, typeDeclaration typescript.TypeDeclaration) typescript.TypeDeclaration {
-	if existingTypeDeclaration, ok := g.typeDeclarations[reflectType]; ok {
+// AccumulateErrors puts Go2TS in accumulated-errors mode.
+//
+// By default, the Add* methods panic as soon as they find a Go type that cannot be represented in
+// TypeScript (e.g. a chan, a func, a map with struct keys, etc.). In accumulated-errors mode, such
+// problems are recorded as *Error values instead, and the offending types are rendered as "any".
+// All accumulated errors are returned by Err() and Render().
+func (g *Go2TS) AccumulateErrors() {
+	g.accumulateErrors = true
+}
+
+// Err returns all the errors accumulated so far as an Errors value, or nil if there are none.
+//
+// Errors are only accumulated in accumulated-errors mode. See AccumulateErrors().
+func (g *Go2TS) Err() error {
+	if len(g.errors) == 0 {
+		return nil
+	}
+	return g.errors
+}
+
+// Warnings returns any problems found so far that don't prevent Go types from being converted to
+// TypeScript, but might indicate mistakes, e.g. unknown `json:...` tag options. Warnings are
+// reported regardless of the error mode, and never cause Render to fail.
+func (g *Go2TS) Warnings() Errors {
+	return g.warnings
+}
+
+// warn reports a problem with the given Go type, found at the given path, that doesn't prevent it
+// from being converted to TypeScript. See Warnings().
+func (g *Go2TS) warn(typ goType, path, reason string) {
+	g.warnings = append(g.warnings, &Error{
+		Type:   typ.reflectType(),
+		Path:   path,
+		Reason: reason,
+	})
+}
+
+// fail reports that the given Go type, found at the given path, cannot be converted to TypeScript.
+// It panics with the given reason unless Go2TS is in accumulated-errors mode.
+func (g *Go2TS) fail(typ goType, path, reason string) {
+	if !g.accumulateErrors {
+		panic(reason)
+	}
+	g.errors = append(g.errors, &Error{
+		Type:   typ.reflectType(),
+		Path:   path,
+		Reason: reason,
+	})
+}
+
+func (g *Go2TS) getOrSaveTypeDeclaration(typ goType, typeDeclaration typescript.TypeDeclaration) typescript.TypeDeclaration {
+	if existingTypeDeclaration, ok := g.typeDeclarations[typ.id()]; ok {
 		return existingTypeDeclaration
 	}
-	g.typeDeclarations[reflectType] = typeDeclaration
+
+	// If the Go type was used as a map key before being declared, any maps keyed by it reference a
+	// placeholder, which we turn into the type declaration, or into an alias for it in the case of
+	// enums, such that said maps reflect the type declaration. See mapKeyTypeToTypeScriptType().
+	if placeholder, ok := g.mapKeyPlaceholders[typ.id()]; ok {
+		delete(g.mapKeyPlaceholders, typ.id())
+		switch declaration := typeDeclaration.(type) {
+		case *typescript.TypeAliasDeclaration:
+			*placeholder = *declaration
+			typeDeclaration = placeholder
+		case *typescript.EnumDeclaration:
+			placeholder.Namespace = declaration.Namespace
+			placeholder.Identifier = declaration.Identifier
+			placeholder.Type = declaration.TypeReference()
+			g.enumAliases[declaration] = append(g.enumAliases[declaration], placeholder)
+		}
+	}
+
+	g.typeDeclarations[typ.id()] = typeDeclaration
 	g.typeDeclarationsInOrder = append(g.typeDeclarationsInOrder, typeDeclaration)
+	g.packagePaths[typeDeclaration] = typ.PkgPath()
 	return typeDeclaration
 }
 
@@ -74,6 +256,7 @@ func (g *Go2TS) AddIgnoreNil(v interface{}) {
 }
 
 // AddToNamespace adds a type that needs a TypeScript definition to the given TypeScript namespace.
+// Nested namespaces are separated by dots, e.g. "api.v1".
 //
 // See AddWithNameToNamespace() for more details.
 func (g *Go2TS) AddToNamespace(v interface{}, namespace string) {
@@ -97,7 +280,8 @@ func (g *Go2TS) AddMultiple(values ...interface{}) {
 
 // AddMultipleToNamespace adds multiple types to the given TypeScript namespace in a single call.
 //
-// Will stop at the first type that fails.
+// By default, it panics at the first type that fails. In accumulated-errors mode, it adds all the
+// types, and records an error for each problem found. See AccumulateErrors().
 func (g *Go2TS) AddMultipleToNamespace(namespace string, values ...interface{}) {
 	for _, v := range values {
 		g.AddToNamespace(v, namespace)
@@ -120,8 +304,10 @@ func (g *Go2TS) AddWithNameIgnoreNil(v interface{}, interfaceName string) {
 
 // AddWithNameToNamespace adds a type that needs a TypeScript definition.
 //
-// The value passed in can be an instance of a type, a reflect.Type, or a
-// reflect.Value.
+// The value passed in can be an instance of a type, a reflect.Type, a
+// reflect.Value, or a types.Type. The latter allows adding Go types loaded from
+// source code via the go/types package, e.g. by the go2ts command, without
+// having to compile a program that imports them.
 //
 // The 'name' supplied will be the TypeScript interface name. If 'interfaceName'
 // is the empty string then the Go type name will be used. If the type is of a
@@ -131,8 +317,11 @@ func (g *Go2TS) AddWithNameIgnoreNil(v interface{}, interfaceName string) {
 // convention for json serialization, including using the json tag if supplied.
 // Fields tagged with `json:",omitempty"` will be marked as optional.
 //
-// There is special handling of time.Time types to be TypeScript "string"s since
-// time.Time implements MarshalJSON, see
+// Types with a custom type mapping are represented as the TypeScript type they
+// map to, see AddCustomType(). By default, types that implement
+// encoding.TextMarshaler are represented as TypeScript "string"s, types that
+// implement json.Marshaler are represented as "any", and time.Time is
+// represented as a "string", see
 // https://pkg.go.dev/time?tab=doc#Time.MarshalJSON.
 //
 // If namespace is non-empty, the type will be added inside a TypeScript
@@ -150,17 +339,12 @@ func (g *Go2TS) AddWithNameToNamespaceIgnoreNil(v interface{}, interfaceName, na
 }
 
 func (g *Go2TS) add(v interface{}, interfaceName, namespace string, ignoreNilPolicy ignoreNilPolicy) {
-	var reflectType reflect.Type
-	switch v := v.(type) {
-	case reflect.Type:
-		reflectType = v
-	case reflect.Value:
-		reflectType = v.Type()
-	default:
-		reflectType = reflect.TypeOf(v)
+	typ := toGoType(v)
+	// Instantiated generic types are declared as their generic types, e.g. Page<T> for Page[Item].
+	if origin, ok := g.genericOrigin(removeIndirection(typ)); ok {
+		typ = origin
 	}
-
-	g.addTypeDeclaration(reflectType, interfaceName, namespace, ignoreNilPolicy)
+	g.addTypeDeclaration(typ, interfaceName, namespace, rootPath(typ), ignoreNilPolicy)
 }
 
 // AddUnion adds a TypeScript definition for a union type of the values in 'v',
@@ -188,7 +372,8 @@ func (g *Go2TS) AddMultipleUnion(values ...interface{}) {
 
 // AddMultipleUnionToNamespace adds multple union types to the given TypeScript namespace.
 //
-// Will stop at the first union type that fails.
+// By default, it panics at the first union type that fails. In accumulated-errors mode, it adds all
+// the union types, and records an error for each problem found. See AccumulateErrors().
 func (g *Go2TS) AddMultipleUnionToNamespace(namespace string, values ...interface{}) {
 	for _, v := range values {
 		g.AddUnionToNamespace(v, namespace)
@@ -213,12 +398,15 @@ func (g *Go2TS) AddUnionWithNameToNamespace(v interface{}, typeName, namespace s
 	// We can only build union types from Go slices or arrays.
 	reflectType := reflect.TypeOf(v)
 	if reflectType.Kind() != reflect.Slice && reflectType.Kind() != reflect.Array {
-		panic(fmt.Sprintf("AddUnionWithName must be supplied an array or slice, got %v: %v", reflectType.Kind(), v))
+		g.fail(reflectGoType{reflectType}, rootPath(reflectGoType{reflectType}), fmt.Sprintf("AddUnionWithName must be supplied an array or slice, got %v: %v", reflectType.Kind(), v))
+		return
 	}
+	elemType := reflectGoType{reflectType.Elem()}
+	path := rootPath(elemType)
 
 	// Make sure we have a name for the union type.
 	if typeName == "" {
-		typeName = reflectType.Elem().Name()
+		typeName = typeIdentifier(elemType)
 	}
 
 	// We will populate the union type with the typescript.LiteralTypes corresponding to the elements
@@ -233,7 +421,7 @@ func (g *Go2TS) AddUnionWithNameToNamespace(v interface{}, typeName, namespace s
 		value := values.Index(i)
 
 		// Obtain the typescript.BasicType corresponding to the current element. We only support basic
-		// types; any other types will result in a panic.
+		// types; any other types will result in an error.
 		var basicType typescript.BasicType
 		if value.Kind() == reflect.Bool {
 			basicType = typescript.Boolean
@@ -242,7 +430,8 @@ func (g *Go2TS) AddUnionWithNameToNamespace(v interface{}, typeName, namespace s
 		} else if value.Kind() == reflect.String {
 			basicType = typescript.String
 		} else {
-			panic(fmt.Sprintf("Go Kind %q cannot be used in a TypeScript union type.", value.Kind()))
+			g.fail(reflectGoType{value.Type()}, fmt.Sprintf("%s[%d]", path, i), fmt.Sprintf("Go Kind %q cannot be used in a TypeScript union type.", value.Kind()))
+			continue
 		}
 
 		// Create a typescript.LiteralType for the current element and add it to the union type.
@@ -252,124 +441,211 @@ func (g *Go2TS) AddUnionWithNameToNamespace(v interface{}, typeName, namespace s
 		})
 	}
 
-	if existingTypeDeclaration, ok := g.typeDeclarations[reflectType.Elem()]; ok {
-		// The reflect.Type was already added, so if it's a TypeScript type alias, we'll update it to be
-		// an alias for the newly added union type.
+	g.addUnionTypeDeclaration(elemType, typeName, namespace, path, unionType)
+}
+
+// addUnionTypeDeclaration declares a TypeScript type alias for the given union type, which
+// represents the values of the given Go type.
+func (g *Go2TS) addUnionTypeDeclaration(typ goType, typeName, namespace, path string, unionType *typescript.UnionType) {
+	if existingTypeDeclaration, ok := g.typeDeclarations[typ.id()]; ok {
+		// The Go type was already added, so if it's a TypeScript type alias, we'll update it to be an
+		// alias for the newly added union type.
 		existingTypeAliasDeclaration, ok := existingTypeDeclaration.(*typescript.TypeAliasDeclaration)
 		if !ok {
-			panic(fmt.Sprintf("Go type %v was already added as something other than a TypeScript type alias.", reflectType.Elem()))
+			g.fail(typ, path, fmt.Sprintf("Go type %v was already added as something other than a TypeScript type alias.", typ))
+			return
 		}
+		g.releaseIdentifier(existingTypeAliasDeclaration.Identifier, existingTypeAliasDeclaration.Namespace)
 		existingTypeAliasDeclaration.Namespace = namespace
-		existingTypeAliasDeclaration.Identifier = typeName
+		existingTypeAliasDeclaration.Identifier = g.declareIdentifier(typ, typeName, namespace, path)
 		existingTypeAliasDeclaration.Type = unionType
 	} else {
-		// The reflect.Type hasn't been seen before, so we declare a new type alias for the union type.
-		g.getOrSaveTypeDeclaration(reflectType.Elem(), &typescript.TypeAliasDeclaration{
+		// The Go type hasn't been seen before, so we declare a new type alias for the union type.
+		g.getOrSaveTypeDeclaration(typ, &typescript.TypeAliasDeclaration{
 			Namespace:  namespace,
-			Identifier: typeName,
+			Identifier: g.declareIdentifier(typ, typeName, namespace, path),
 			Type:       unionType,
+			Doc:        g.typeDoc(typ),
 		})
 	}
 }
 
 // Render the TypeScript definitions to the given io.Writer.
+//
+// The TypeScript definitions in each namespace are grouped in a single namespace block, e.g. all
+// types added to the "api.v1" namespace are rendered inside "export namespace api { export namespace
+// v1 { ... } }".
+//
+// The TypeScript code is formatted as per the render options. See SetRenderOptions().
+//
+// In accumulated-errors mode, Render writes nothing and returns the accumulated errors if there are
+// any. See AccumulateErrors().
 func (g *Go2TS) Render(w io.Writer) error {
-	_, err := fmt.Fprintln(w, "// DO NOT EDIT. This file is automatically generated.")
-	if err != nil {
+	if err := g.Err(); err != nil {
 		return err
 	}
 
-	// Output TypeScript interfaces first.
-	for _, typeDeclaration := range g.typeDeclarationsInOrder {
-		if _, ok := typeDeclaration.(*typescript.InterfaceDeclaration); !ok {
-			continue
+	typeDeclarations := g.typeDeclarationsInRenderOrder()
+
+	// Group the type declarations by namespace, such that each namespace is rendered as a single
+	// namespace block.
+	var global typescript.Namespace
+	for _, typeDeclaration := range typeDeclarations {
+		global.AddTypeDeclaration(typeDeclaration)
+	}
+
+	// Output type guards last, in the same order as their type declarations.
+	if g.emitTypeGuards {
+		for _, typeDeclaration := range typeDeclarations {
+			global.AddTypeGuard(typeDeclaration)
 		}
-		fmt.Fprintln(w)
-		fmt.Fprintln(w, typeDeclaration.ToTypeScript())
 	}
 
-	// Output any other type definitions (e.g. type aliases) second.
+	_, err := io.WriteString(w, g.renderOptions.File(global.ToTypeScriptWithOptions(g.renderOptions)))
+	return err
+}
+
+// typeDeclarationsInRenderOrder returns the type declarations in the order they are rendered, which
+// is TypeScript interfaces first, and any other type declarations (e.g. type aliases) second, unless
+// a sort order is set. See SetSortOrder().
+func (g *Go2TS) typeDeclarationsInRenderOrder() []typescript.TypeDeclaration {
+	if g.sortOrder != AddedOrder {
+		return g.TypeDeclarations()
+	}
+
+	var typeDeclarations []typescript.TypeDeclaration
 	for _, typeDeclaration := range g.typeDeclarationsInOrder {
 		if _, ok := typeDeclaration.(*typescript.InterfaceDeclaration); ok {
-			continue
+			typeDeclarations = append(typeDeclarations, typeDeclaration)
+		}
+	}
+	for _, typeDeclaration := range g.typeDeclarationsInOrder {
+		if _, ok := typeDeclaration.(*typescript.InterfaceDeclaration); !ok {
+			typeDeclarations = append(typeDeclarations, typeDeclaration)
 		}
-		fmt.Fprintln(w)
-		fmt.Fprintln(w, typeDeclaration.ToTypeScript())
 	}
+	return typeDeclarations
+}
+
+// TypeDeclarations returns the TypeScript type declarations for all the Go types added so far, in
+// the order they were added. Any type declarations a given type declaration depends on are returned
+// before it, unless they are mutually recursive, or a sort order is set (see SetSortOrder()).
+//
+// This can be used to write alternate renderers for the TypeScript types, e.g. see the zod package.
+// Note that in accumulated-errors mode, Err() should be checked first.
+//
+// The identifiers of the type declarations are only final once all Go types are added: if a sort
+// order is set, adding more Go types can rename type declarations returned earlier, e.g. to resolve
+// a name collision (see SetSortOrder()).
+func (g *Go2TS) TypeDeclarations() []typescript.TypeDeclaration {
+	g.resolveNameCollisionsInSortOrder()
+	typeDeclarations := append([]typescript.TypeDeclaration{}, g.typeDeclarationsInOrder...)
+	g.sortTypeDeclarations(typeDeclarations)
+	return typeDeclarations
+}
+
+// EmitTypeGuards makes Render output a TypeScript type guard function for each type declaration,
+// e.g. "export function isTurtle(x: unknown): x is Turtle", which can be used to validate values of
+// unknown types at runtime, such as parsed JSON. See typescript.TypeGuard() for details.
+func (g *Go2TS) EmitTypeGuards() {
+	g.emitTypeGuards = true
+}
+
+// SetRenderOptions sets how Render() and RenderModules() format the TypeScript code, e.g. its
+// indentation, quotes, semicolons, header comment and line endings, such that the output matches
+// the configuration of a code formatter such as prettier. See typescript.RenderOptions for the
+// defaults.
+func (g *Go2TS) SetRenderOptions(opts typescript.RenderOptions) {
+	g.renderOptions = opts
+}
 
-	return nil
+// SetMaxTupleLength makes Go2TS declare Go arrays of up to the given length as TypeScript tuples,
+// e.g. a [3]float64 as [number, number, number], rather than as TypeScript arrays, e.g. number[],
+// which lose the length. Longer arrays are still declared as TypeScript arrays.
+//
+// It must be called before adding any types. By default, no arrays are declared as tuples.
+func (g *Go2TS) SetMaxTupleLength(maxTupleLength int) {
+	g.maxTupleLength = maxTupleLength
 }
 
-func (g *Go2TS) addTypeDeclaration(reflectType reflect.Type, typeName, namespace string, ignoreNilPolicy ignoreNilPolicy) {
-	// Struct types are declared as TypeScript interfaces.
-	if removeIndirection(reflectType).Kind() == reflect.Struct {
-		g.addInterfaceDeclaration(reflectType, typeName, namespace, ignoreNilPolicy)
+func (g *Go2TS) addTypeDeclaration(typ goType, typeName, namespace, path string, ignoreNilPolicy ignoreNilPolicy) {
+	// Struct types are declared as TypeScript interfaces, unless they have a custom type mapping.
+	// Values passed to the Add* methods are only addressable if they're pointers.
+	if removeIndirection(typ).Kind() == reflect.Struct && !g.isCustomType(removeIndirection(typ), typ.Kind() == reflect.Ptr) {
+		g.addInterfaceDeclaration(typ, typeName, namespace, path, ignoreNilPolicy)
 		return
 	}
 
 	// All other type declarations are handled as type aliases (except for union types, which are
 	// handled separately).
-	if _, ok := g.typeDeclarations[reflectType]; ok {
+	if _, ok := g.typeDeclarations[typ.id()]; ok {
 		return
 	}
 
 	if typeName == "" {
-		typeName = reflectType.Name()
+		typeName = typeIdentifier(typ)
 	}
 	typeDeclaration := &typescript.TypeAliasDeclaration{
-		Namespace:  namespace,
-		Identifier: typeName,
-		Type:       g.reflectTypeToTypeScriptType(reflectType, namespace, ignoreNilPolicy, explicitlyDiscovered),
+		Namespace:      namespace,
+		Identifier:     g.declareIdentifier(typ, typeName, namespace, path),
+		Type:           g.goTypeToTypeScriptType(typ, namespace, path, ignoreNilPolicy, explicitlyDiscovered, false),
+		TypeParameters: g.typeParametersOf(typ),
+		Doc:            g.typeDoc(typ),
 	}
 
-	g.getOrSaveTypeDeclaration(reflectType, typeDeclaration)
+	g.getOrSaveTypeDeclaration(typ, typeDeclaration)
 }
 
-func (g *Go2TS) addInterfaceDeclaration(structType reflect.Type, interfaceName, namespace string, ignoreNilPolicy ignoreNilPolicy) *typescript.InterfaceDeclaration {
+func (g *Go2TS) addInterfaceDeclaration(structType goType, interfaceName, namespace, path string, ignoreNilPolicy ignoreNilPolicy) *typescript.InterfaceDeclaration {
 	structType = removeIndirection(structType)
 
-	// Only structs can be declared as TypeScript interfaces.
+	// Only structs can be declared as TypeScript interfaces. Callers should guarantee this, so we
+	// panic regardless of the error mode.
 	if structType.Kind() != reflect.Struct {
 		panic(fmt.Sprintf(`Go Kind %q cannot be declared as a TypeScript interface.`, structType.Kind()))
 	}
 
 	// Nothing to do if the TypeScript interface has already been declared.
-	if existingTypeDeclaration, ok := g.typeDeclarations[structType]; ok {
+	if existingTypeDeclaration, ok := g.typeDeclarations[structType.id()]; ok {
 		return existingTypeDeclaration.(*typescript.InterfaceDeclaration)
 	}
 
 	// Make sure we have a name for the interface, which could be anonymous.
 	if interfaceName == "" {
-		interfaceName = strings.Title(structType.Name())
+		interfaceName = strings.Title(typeIdentifier(structType))
 	}
 	if interfaceName == "" {
-		interfaceName = g.getAnonymousInterfaceName()
+		interfaceName = g.getAnonymousInterfaceName(structType)
 	}
 
 	// Create the interface declaration.
 	interfaceDeclaration := &typescript.InterfaceDeclaration{
-		Namespace:  namespace,
-		Identifier: interfaceName,
-		Properties: []typescript.PropertySignature{},
+		Namespace:      namespace,
+		Identifier:     g.declareIdentifier(structType, interfaceName, namespace, path),
+		Properties:     []typescript.PropertySignature{},
+		TypeParameters: g.typeParametersOf(structType),
+		Doc:            g.typeDoc(structType),
 	}
 
 	// Save the interface declaration before populating its fields. This guarantees that we won't get
 	// stuck in an infinite recursion if the Go struct is recursive (e.g. type Foo struct { F *Foo }).
-	g.typeDeclarations[structType] = interfaceDeclaration
+	g.typeDeclarations[structType.id()] = interfaceDeclaration
 
 	// Populate the interface fields. This will recurse into any embedded structs.
-	g.populateInterfaceDeclarationProperties(interfaceDeclaration, structType, ignoreNilPolicy, doNotRecursivelyForceOptional)
+	g.populateInterfaceDeclarationProperties(interfaceDeclaration, structType, path, ignoreNilPolicy, doNotRecursivelyForceOptional)
 
 	// Add the interface declaration to the ordered output after populating its fields. This ensures
 	// that any new types discovered while populating the interface fields will appear before the
 	// interface declaration in the output TypeScript code.
 	g.typeDeclarationsInOrder = append(g.typeDeclarationsInOrder, interfaceDeclaration)
+	g.packagePaths[interfaceDeclaration] = structType.PkgPath()
 
 	return interfaceDeclaration
 }
 
-func (g *Go2TS) getAnonymousInterfaceName() string {
+func (g *Go2TS) getAnonymousInterfaceName(structType goType) string {
 	g.anonymousCount++
+	g.anonymousTypes = append(g.anonymousTypes, structType)
 	return fmt.Sprintf("Anonymous%d", g.anonymousCount)
 }
 
@@ -386,19 +662,22 @@ const (
 )
 
 // populateInterfaceDeclarationProperties recursively populates the properties of the given
-// interface declaration. It assumes reflectType's Kind is Struct.
+// interface declaration. It assumes structType's Kind is Struct.
+//
+// The path is used to report errors, and identifies the struct type starting from the type passed
+// to one of the Add* methods.
 //
 // If the optionalFieldPolicy is recursivelyForceOptional, any properties populated on
 // this or any recursive calls to this method will be marked as optional.
-func (g *Go2TS) populateInterfaceDeclarationProperties(interfaceDeclaration *typescript.InterfaceDeclaration, structType reflect.Type, ignoreNilPolicy ignoreNilPolicy, optionalFieldPolicy optionalFieldPolicy) {
-	isEmbeddedStruct := func(f reflect.StructField) bool {
+func (g *Go2TS) populateInterfaceDeclarationProperties(interfaceDeclaration *typescript.InterfaceDeclaration, structType goType, path string, ignoreNilPolicy ignoreNilPolicy, optionalFieldPolicy optionalFieldPolicy) {
+	isEmbeddedStruct := func(f goStructField) bool {
 		return f.Anonymous && removeIndirection(f.Type).Kind() == reflect.Struct
 	}
 
 	// Iterate over normal fields first, and embedded structs last. This ensures that outer fields
 	// will take precedence over inner fields in the case of overlapping fields, which is consistent
 	// with json.Marshal().
-	var normalFields, embeddedStructFields []reflect.StructField
+	var normalFields, embeddedStructFields []goStructField
 	for i := 0; i < structType.NumField(); i++ {
 		field := structType.Field(i)
 		if isEmbeddedStruct(field) {
@@ -415,6 +694,8 @@ func (g *Go2TS) populateInterfaceDeclarationProperties(interfaceDeclaration *typ
 			continue
 		}
 
+		fieldPath := path + "." + structField.Name
+
 		// If the field is an embedded struct, or an embedded struct pointer, we add the inner struct's
 		// fields to the outer struct (i.e. we flatten the structs). This is consistent with
 		// json.Marshal().
@@ -426,24 +707,24 @@ func (g *Go2TS) populateInterfaceDeclarationProperties(interfaceDeclaration *typ
 				embeddedStructOptionalFieldPolicy = recursivelyForceOptional
 			}
 
-			g.populateInterfaceDeclarationProperties(interfaceDeclaration, removeIndirection(structField.Type), ignoreNilPolicy, embeddedStructOptionalFieldPolicy)
+			g.populateInterfaceDeclarationProperties(interfaceDeclaration, removeIndirection(structField.Type), fieldPath, ignoreNilPolicy, embeddedStructOptionalFieldPolicy)
 			continue
 		}
 
 		// Read the field's `json:...` tag.
-		jsonTag := strings.Split(structField.Tag.Get("json"), ",")
-
-		// Read the property name from the `json:...` tag, or default to the field name.
-		propertyName := structField.Name
-		if len(jsonTag) > 0 && jsonTag[0] != "" {
-			propertyName = jsonTag[0]
-		}
+		jsonTag := parseJSONTag(structField.Tag.Get("json"))
 
 		// A `json:"-"` tag means the field will not be serialized to JSON, so we can skip it.
-		if propertyName == "-" {
+		if jsonTag.ignored {
 			continue
 		}
 
+		// Read the property name from the `json:...` tag, or default to the field name.
+		propertyName := structField.Name
+		if jsonTag.name != "" {
+			propertyName = jsonTag.name
+		}
+
 		// If there's already a field with the same name as the current field, we skip it. This can
 		// happen when populating the fields of an embedded struct, and the inner and outer structs
 		// have overlapping fields, in which case the outer fields takes precedence.
@@ -458,6 +739,14 @@ func (g *Go2TS) populateInterfaceDeclarationProperties(interfaceDeclaration *typ
 			continue
 		}
 
+		// Invalid names and unknown options are ignored by json.Marshal, but they're likely mistakes.
+		if jsonTag.invalidName != "" {
+			g.warn(structField.Type, fieldPath, fmt.Sprintf("Invalid json tag name %q", jsonTag.invalidName))
+		}
+		for _, option := range jsonTag.unknownOptions {
+			g.warn(structField.Type, fieldPath, fmt.Sprintf("Unknown json tag option %q", option))
+		}
+
 		// A `go2ts:"ignorenil"` tag means that any nillable types will be treated as their non-nillable
 		// counterparts when recursively computing the TypeScript type of the current field. Concretely,
 		// this means that pointers will have the indirection removed, slices will be treated as
@@ -468,28 +757,129 @@ func (g *Go2TS) populateInterfaceDeclarationProperties(interfaceDeclaration *typ
 		// defined as "type Foo []string", and it's annotated with `go2ts:"ignorenil"`, then the
 		// TypeScript type Foo will be declared as "type Foo = string[]" instead of
 		// "type Foo = string[] | null".
-		hasIgnoreNilTag := structField.Tag.Get("go2ts") == "ignorenil"
+		go2tsTag := parseGo2TSTag(structField.Tag.Get("go2ts"))
+		for _, option := range go2tsTag.unknownOptions {
+			g.warn(structField.Type, fieldPath, fmt.Sprintf("Unknown go2ts tag option %q", option))
+		}
+		hasIgnoreNilTag := go2tsTag.ignoreNil
 
 		// Recursively compute the property's TypeScript type.
 		propertyIgnoreNilPolicy := doNotIgnoreNil
 		if ignoreNilPolicy == ignoreNil || hasIgnoreNilTag {
 			propertyIgnoreNilPolicy = ignoreNil
 		}
-		propertyType := g.reflectTypeToTypeScriptType(structField.Type, interfaceDeclaration.Namespace, propertyIgnoreNilPolicy, implicitlyDiscovered)
+		// Fields tagged with the "string" option might be encoded as JSON strings. See
+		// quotedTypeScriptType() for details.
+		var propertyType typescript.Type
+		quoted := false
+		if jsonTag.quoted {
+			propertyType, quoted = g.quotedTypeScriptType(structField.Type, propertyIgnoreNilPolicy)
+		}
+		if !quoted {
+			// Struct declarations are shared by all the values of a struct type, thus we assume the
+			// fields are addressable, as is the case for structs reached through pointers or slices.
+			propertyType = g.goTypeToTypeScriptType(structField.Type, interfaceDeclaration.Namespace, fieldPath, propertyIgnoreNilPolicy, implicitlyDiscovered, true)
+		}
 
-		// We mark the property as optional if the field is tagged with "omitempty".
-		markedAsOptional := len(jsonTag) > 1 && jsonTag[1] == "omitempty"
+		// We mark the property as optional if the field is tagged with "omitempty" or "omitzero".
+		markedAsOptional := jsonTag.optional()
+
+		// A `go2ts:"readonly"` tag makes the property readonly, along with any arrays and maps in its
+		// type. See EmitReadonly().
+		if go2tsTag.readonly && !g.emitReadonly {
+			propertyType = readonlyType(propertyType)
+		}
 
 		// Create the property signature and add it to the interface declaration.
 		property := typescript.PropertySignature{
 			Identifier: propertyName,
 			Type:       propertyType,
 			Optional:   optionalFieldPolicy == recursivelyForceOptional || markedAsOptional,
+			Readonly:   g.emitReadonly || go2tsTag.readonly,
+			Doc:        g.fieldDoc(structType, structField.Name),
 		}
 		interfaceDeclaration.Properties = append(interfaceDeclaration.Properties, property)
 	}
 }
 
+// mapKeyTypeToTypeScriptType returns the TypeScript index type for the given Go map key type,
+// consistent with how json.Marshal encodes map keys:
+//   - Keys of string Kinds are used directly.
+//   - Keys implementing encoding.TextMarshaler (with value receivers) are encoded as strings.
+//   - Keys of number Kinds are encoded as numbers in strings.
+//
+// Named string key types without custom type mappings (e.g. "type Direction string") are returned
+// as references to their TypeScript declarations. If said declarations are union types or enums
+// when rendered, the map will be rendered as a mapped type, e.g. "{ [key in Direction]?: V }",
+// otherwise it will be rendered as an index signature, e.g. "{ [key: string]: V }".
+//
+// Named string key types are not declared merely because they are used as map keys, unless their
+// constants are discovered as a union type (see DiscoverConstUnions()). Instead, if they aren't
+// declared yet, they are returned as references to an undeclared placeholder, which becomes their
+// declaration if they are declared later, e.g. via AddUnion(). See getOrSaveTypeDeclaration().
+//
+// The path is used to report errors.
+func (g *Go2TS) mapKeyTypeToTypeScriptType(keyType goType, namespace, path string) typescript.Type {
+	// TypeScript index signature parameter types cannot be type parameters, thus we fall back to
+	// strings, which is how json.Marshal encodes map keys anyway.
+	if g.isTypeParameter(keyType) {
+		return typescript.String
+	}
+	if keyType.Kind() == reflect.String {
+		if !isPrimitiveAlias(keyType) || g.isCustomType(keyType, false) {
+			return typescript.String
+		}
+		if _, ok := g.typeDeclarations[keyType.id()]; ok {
+			return g.goTypeToTypeScriptType(keyType, namespace, path, ignoreNil, implicitlyDiscovered, false)
+		}
+		if _, ok := g.discoveredConstUnionType(keyType); ok {
+			return g.goTypeToTypeScriptType(keyType, namespace, path, ignoreNil, implicitlyDiscovered, false)
+		}
+		placeholder, ok := g.mapKeyPlaceholders[keyType.id()]
+		if !ok {
+			placeholder = &typescript.TypeAliasDeclaration{
+				Namespace:  namespace,
+				Identifier: typeIdentifier(keyType),
+				Type:       typescript.String,
+			}
+			g.mapKeyPlaceholders[keyType.id()] = placeholder
+		}
+		return placeholder.TypeReference()
+	}
+	if keyType.implements(textMarshalerType, false) {
+		return typescript.String
+	}
+	if isNumber(keyType.Kind()) {
+		return typescript.Number
+	}
+	g.fail(keyType, path, fmt.Sprintf("Go Kind %q cannot be used as a TypeScript index signature parameter type.", keyType.Kind()))
+	return typescript.String
+}
+
+// quotedTypeScriptType returns the TypeScript type of a struct field of the given Go type tagged
+// with the `json:",string"` option, and true, or nil and false if the option doesn't apply to the
+// given Go type.
+//
+// Consistent with json.Marshal, the option only applies to boolean, number and string fields, or
+// unnamed pointers to such types, which are encoded as JSON strings. Go types with custom type
+// mappings (e.g. encoding.TextMarshaler implementations) are not affected by the option.
+func (g *Go2TS) quotedTypeScriptType(typ goType, ignoreNilPolicy ignoreNilPolicy) (typescript.Type, bool) {
+	nillable := false
+	if typ.Kind() == reflect.Ptr && typ.Name() == "" {
+		typ = typ.Elem()
+		nillable = true
+	}
+	if !isPrimitive(typ.Kind()) || g.isCustomType(typ, true) {
+		return nil, false
+	}
+	if nillable && ignoreNilPolicy == doNotIgnoreNil {
+		return &typescript.UnionType{
+			Types: []typescript.Type{typescript.String, typescript.Null},
+		}, true
+	}
+	return typescript.String, true
+}
+
 // typeDiscovery indicates whether a Go type was explicitly added to a Go2TS instance via one of
 // the Add* methods, or implicitly, e.g. by discovering a user-defined type when inspecting the
 // field types of a Go struct type explicitly added by the user.
@@ -500,11 +890,21 @@ const (
 	implicitlyDiscovered
 )
 
-func (g *Go2TS) reflectTypeToTypeScriptType(reflectType reflect.Type, namespace string, ignoreNilPolicy ignoreNilPolicy, typeDiscovery typeDiscovery) typescript.Type {
+// goTypeToTypeScriptType returns the TypeScript type corresponding to the given Go type.
+//
+// The path is used to report errors, and identifies the Go type starting from the type passed to one
+// of the Add* methods, e.g. "Farm.Stats.key". If the Go type cannot be converted to TypeScript, and
+// Go2TS is in accumulated-errors mode, the error is recorded and the "any" type is returned.
+//
+// The addressable argument indicates whether values of the Go type are addressable, which
+// determines whether marshaler methods with pointer receivers apply. See
+// customTypeToTypeScriptType().
+func (g *Go2TS) goTypeToTypeScriptType(typ goType, namespace, path string, ignoreNilPolicy ignoreNilPolicy, typeDiscovery typeDiscovery, addressable bool) typescript.Type {
 	// If the type is a pointer, then we remove the pointer indirection, compute the resulting
 	// TypeScript type, and return the union between that type and null.
-	if reflectType.Kind() == reflect.Ptr {
-		tsType := g.reflectTypeToTypeScriptType(removeIndirection(reflectType), namespace, ignoreNilPolicy, typeDiscovery)
+	if typ.Kind() == reflect.Ptr {
+		// Values reached through pointers are addressable.
+		tsType := g.goTypeToTypeScriptType(removeIndirection(typ), namespace, path, ignoreNilPolicy, typeDiscovery, true)
 		if ignoreNilPolicy == ignoreNil {
 			return tsType
 		}
@@ -513,100 +913,140 @@ func (g *Go2TS) reflectTypeToTypeScriptType(reflectType reflect.Type, namespace
 		}
 	}
 
-	// If we have declared this type before, then we just return a reference to the declared type.
-	if existingTypeDeclaration, ok := g.typeDeclarations[reflectType]; ok {
-		return existingTypeDeclaration.TypeReference()
+	// Type parameters and instantiated generic types are handled separately. See EmitGenerics().
+	if tsType, ok := g.genericTypeToTypeScriptType(typ, namespace, path, ignoreNilPolicy, addressable); ok {
+		return tsType
+	}
+
+	// Types with a custom type mapping (e.g. json.Marshaler implementations) are represented as the
+	// TypeScript type they map to, so we ignore their Kind and, in the case of structs, their fields.
+	customTSType, isCustomType := g.customTypeToTypeScriptType(typ, addressable)
+
+	// If we have declared this type before, then we just return a reference to the declared type,
+	// unless it's a struct with a custom type mapping that was declared as an interface where it has
+	// none, i.e. where its marshaler methods with pointer receivers don't apply.
+	if existingTypeDeclaration, ok := g.typeDeclarations[typ.id()]; ok {
+		if _, isInterface := existingTypeDeclaration.(*typescript.InterfaceDeclaration); !isCustomType || !isInterface {
+			return existingTypeDeclaration.TypeReference()
+		}
 	}
 
-	// Structs are declared as interfaces (save for time.Time, which is a special case handled below).
-	if reflectType.Kind() == reflect.Struct && !isTime(reflectType) {
-		return g.addInterfaceDeclaration(reflectType, "", namespace, ignoreNilPolicy).TypeReference()
+	// Structs are declared as interfaces (save for custom types, which are handled below).
+	if typ.Kind() == reflect.Struct && !isCustomType {
+		return g.addInterfaceDeclaration(typ, "", namespace, path, ignoreNilPolicy).TypeReference()
 	}
 
-	// Will hold the typescript.Type extracted from the reflect.Type.
+	// Will hold the typescript.Type extracted from the Go type.
 	var tsType typescript.Type
 
-	// Compute the TypeScript type based on the Kind of the reflected type.
-	switch reflectType.Kind() {
-	case reflect.Uint8,
-		reflect.Uint16,
-		reflect.Uint32,
-		reflect.Uint64,
-		reflect.Uint,
-		reflect.Int8,
-		reflect.Int16,
-		reflect.Int32,
-		reflect.Int64,
-		reflect.Int,
-		reflect.Float32,
-		reflect.Float64:
-		tsType = typescript.Number
-
-	case reflect.String:
-		tsType = typescript.String
-
-	case reflect.Bool:
-		tsType = typescript.Boolean
-
-	case reflect.Map:
-		// TypeScript index signature parameter types[1] must be either "string" or "number", and
-		// cannot be type aliases, otherwise the TypeScript compiler will fail with error
-		// "TS1336: An index signature parameter type cannot be a type alias.".
-		//
-		// Example:
-		//
-		//   export type Foo = string;
-		//   export type Bar = { [key: Foo]: string };  // Compiler produces error TS1336.
-		//
-		// Thus, we treat map keys as a special case where we ignore any type aliases and use either
-		// "string" or "number" directly.
-		//
-		// [1] https://www.typescriptlang.org/docs/handbook/advanced-types.html#index-types-and-index-signatures.
-		var indexType typescript.Type
-		if reflectType.Key().Kind() == reflect.String {
-			indexType = typescript.String
-		} else if isNumber(reflectType.Key().Kind()) {
-			indexType = typescript.Number
-		} else {
-			panic(fmt.Sprintf("Go Kind %q cannot be used as a TypeScript index signature parameter type.", reflectType.Key().Kind()))
-		}
+	if isCustomType {
+		tsType = customTSType
+	} else if unionType, ok := g.discoveredConstUnionType(typ); ok {
+		tsType = unionType
+	} else {
+		// Compute the TypeScript type based on the Kind of the reflected type.
+		switch typ.Kind() {
+		case reflect.Uint8,
+			reflect.Uint16,
+			reflect.Uint32,
+			reflect.Uint64,
+			reflect.Uint,
+			reflect.Uintptr,
+			reflect.Int8,
+			reflect.Int16,
+			reflect.Int32,
+			reflect.Int64,
+			reflect.Int,
+			reflect.Float32,
+			reflect.Float64:
+			tsType = typescript.Number
+
+		case reflect.String:
+			tsType = typescript.String
+
+		case reflect.Bool:
+			tsType = typescript.Boolean
+
+		case reflect.Map:
+			// TypeScript index signature parameter types[1] must be either "string" or "number", and
+			// cannot be type aliases, otherwise the TypeScript compiler will fail with error
+			// "TS1336: An index signature parameter type cannot be a type alias.".
+			//
+			// Example:
+			//
+			//   export type Foo = string;
+			//   export type Bar = { [key: Foo]: string };  // Compiler produces error TS1336.
+			//
+			// Thus, we treat map keys as a special case where we use either "string" or "number" directly,
+			// or a reference to a type alias that typescript.MapType resolves at render time. See
+			// mapKeyTypeToTypeScriptType().
+			//
+			// [1] https://www.typescriptlang.org/docs/handbook/advanced-types.html#index-types-and-index-signatures.
+			tsType = &typescript.MapType{
+				IndexType: g.mapKeyTypeToTypeScriptType(typ.Key(), namespace, path+".key"),
+				// Map values are not addressable.
+				ValueType: g.goTypeToTypeScriptType(typ.Elem(), namespace, path+".value", ignoreNilPolicy, implicitlyDiscovered, false),
+				Readonly:  g.emitReadonly,
+			}
 
-		tsType = &typescript.MapType{
-			IndexType: indexType,
-			ValueType: g.reflectTypeToTypeScriptType(reflectType.Elem(), namespace, ignoreNilPolicy, implicitlyDiscovered),
-		}
+			// Maps can be nil.
+			if ignoreNilPolicy == doNotIgnoreNil {
+				tsType = &typescript.UnionType{
+					Types: []typescript.Type{tsType, typescript.Null},
+				}
+			}
 
-		// Maps can be nil.
-		if ignoreNilPolicy == doNotIgnoreNil {
-			tsType = &typescript.UnionType{
-				Types: []typescript.Type{tsType, typescript.Null},
+		case reflect.Slice, reflect.Array:
+			// Byte slices are encoded as base64 strings by json.Marshal. See isByteSlice().
+			if isByteSlice(typ) {
+				tsType = typescript.String
+				if ignoreNilPolicy == doNotIgnoreNil {
+					tsType = &typescript.UnionType{
+						Types: []typescript.Type{tsType, typescript.Null},
+					}
+				}
+				break
 			}
-		}
 
-	case reflect.Slice, reflect.Array:
-		tsType = &typescript.ArrayType{
-			ItemsType: g.reflectTypeToTypeScriptType(reflectType.Elem(), namespace, ignoreNilPolicy, implicitlyDiscovered),
-		}
-		// Slices can be nil, but not arrays.
-		if reflectType.Kind() == reflect.Slice && ignoreNilPolicy == doNotIgnoreNil {
-			tsType = &typescript.UnionType{
-				Types: []typescript.Type{tsType, typescript.Null},
+			// Go arrays have a fixed length, thus short ones are declared as tuples. See
+			// SetMaxTupleLength().
+			// Slice elements are addressable, and so are the elements of addressable arrays.
+			itemsType := g.goTypeToTypeScriptType(typ.Elem(), namespace, path+"[]", ignoreNilPolicy, implicitlyDiscovered, addressable || typ.Kind() == reflect.Slice)
+			if typ.Kind() == reflect.Array && g.maxTupleLength > 0 && typ.Len() <= g.maxTupleLength {
+				tupleType := &typescript.TupleType{Readonly: g.emitReadonly}
+				for i := 0; i < typ.Len(); i++ {
+					tupleType.ElementTypes = append(tupleType.ElementTypes, itemsType)
+				}
+				tsType = tupleType
+				break
+			}
+
+			tsType = &typescript.ArrayType{
+				ItemsType: itemsType,
+				Readonly:  g.emitReadonly,
+			}
+			// Slices can be nil, but not arrays.
+			if typ.Kind() == reflect.Slice && ignoreNilPolicy == doNotIgnoreNil {
+				tsType = &typescript.UnionType{
+					Types: []typescript.Type{tsType, typescript.Null},
+				}
 			}
-		}
 
-	case reflect.Struct:
-		// This is necessarily a time.Time because we handled all other structs earlier.
-		tsType = typescript.String
+		case reflect.Interface:
+			tsType = typescript.Any
 
-	case reflect.Interface:
-		tsType = typescript.Any
+		case reflect.Complex64,
+			reflect.Complex128,
+			reflect.Chan,
+			reflect.Func,
+			reflect.UnsafePointer:
+			g.fail(typ, path, fmt.Sprintf("Go Kind %q cannot be serialized to JSON.", typ.Kind()))
+			return typescript.Any
 
-	case reflect.Complex64,
-		reflect.Complex128,
-		reflect.Chan,
-		reflect.Func,
-		reflect.UnsafePointer:
-		panic(fmt.Sprintf("Go Kind %q cannot be serialized to JSON.", reflectType.Kind()))
+		default:
+			g.fail(typ, path, fmt.Sprintf("Go Kind %q is not supported.", typ.Kind()))
+			return typescript.Any
+		}
 	}
 
 	// If this is a named Go type (e.g. "Donut", assuming we have added a "type Donut string" Go type)
@@ -618,38 +1058,62 @@ func (g *Go2TS) reflectTypeToTypeScriptType(reflectType reflect.Type, namespace
 	// one of the Go2TS.Add*() methods because said methods will add the type declarations themselves.
 	if typeDiscovery == implicitlyDiscovered &&
 		// All type aliases have a non-empty name.
-		reflectType.Name() != "" &&
+		typ.Name() != "" &&
 		// But not all types with non-empty names are aliases (e.g. the name for the int type is "int").
-		(!isPrimitive(reflectType.Kind()) || isPrimitiveAlias(reflectType)) &&
-		// We don't want an alias for time.Time because we treat it as a string in TypeScript.
-		!isTime(reflectType) {
+		(!isPrimitive(typ.Kind()) || isPrimitiveAlias(typ)) &&
+		// We don't want aliases for custom struct types such as time.Time or big.Int, because names
+		// such as "Time" or "Int" would be confusing in TypeScript. The same goes for json.RawMessage.
+		!(isCustomType && (typ.Kind() == reflect.Struct || isType(reflectGoType{rawMessageType})(typ))) {
+		// The type might have been declared while computing its TypeScript type, e.g. if it's recursive.
+		if existingTypeDeclaration, ok := g.typeDeclarations[typ.id()]; ok {
+			return existingTypeDeclaration.TypeReference()
+		}
+
 		typeDeclaration := &typescript.TypeAliasDeclaration{
-			Namespace:  namespace,
-			Identifier: reflectType.Name(),
-			Type:       tsType,
+			Namespace:      namespace,
+			Identifier:     g.declareIdentifier(typ, typeIdentifier(typ), namespace, path),
+			Type:           tsType,
+			TypeParameters: g.typeParametersOf(typ),
+			Doc:            g.typeDoc(typ),
 		}
 
-		// If we've already added a TypeScript type declaration for this Go type, we'll return a
-		// reference to the existing declaration, otherwise we'll return a reference to the new
-		// declaration.
-		return g.getOrSaveTypeDeclaration(reflectType, typeDeclaration).TypeReference()
+		return g.getOrSaveTypeDeclaration(typ, typeDeclaration).TypeReference()
 	}
 
 	return tsType
 }
 
-func removeIndirection(reflectType reflect.Type) reflect.Type {
-	kind := reflectType.Kind()
-	// Follow all the pointers until we get to a non-Ptr kind.
-	for kind == reflect.Ptr {
-		reflectType = reflectType.Elem()
-		kind = reflectType.Kind()
+// discoveredConstUnionType returns a union type of the values of the constants of the given Go type
+// and true, or nil and false if no such constants exist, or Go2TS isn't configured to discover them.
+// See DiscoverConstUnions().
+func (g *Go2TS) discoveredConstUnionType(typ goType) (*typescript.UnionType, bool) {
+	if !g.discoverConstUnions || !isPrimitiveAlias(typ) {
+		return nil, false
+	}
+	unionType, err := g.constUnionType(typ)
+	if err != nil {
+		return nil, false
+	}
+	return unionType, true
+}
+
+// rootPath returns the path used in error messages for a type passed to one of the Add* methods.
+func rootPath(typ goType) string {
+	typ = removeIndirection(typ)
+	if typ.Name() != "" {
+		return typ.Name()
 	}
-	return reflectType
+	return typ.String()
 }
 
-func isTime(t reflect.Type) bool {
-	return t.Name() == "Time" && t.PkgPath() == "time"
+func removeIndirection(typ goType) goType {
+	kind := typ.Kind()
+	// Follow all the pointers until we get to a non-Ptr kind.
+	for kind == reflect.Ptr {
+		typ = typ.Elem()
+		kind = typ.Kind()
+	}
+	return typ
 }
 
 // numbers is the set of Kinds that we convert into the TypeScript "number" type.
@@ -664,6 +1128,7 @@ var numberKinds = map[reflect.Kind]bool{
 	reflect.Uint16:  true,
 	reflect.Uint32:  true,
 	reflect.Uint64:  true,
+	reflect.Uintptr: true,
 	reflect.Float32: true,
 	reflect.Float64: true,
 }
@@ -674,15 +1139,26 @@ func isNumber(kind reflect.Kind) bool {
 
 // nonNumberPrimitiveKinds is the non-number set of Kinds that we support converting to TypeScript.
 var nonNumberPrimitiveKinds = map[reflect.Kind]bool{
-	reflect.Bool:    true,
-	reflect.Uintptr: true,
-	reflect.String:  true,
+	reflect.Bool:   true,
+	reflect.String: true,
 }
 
 func isPrimitive(kind reflect.Kind) bool {
 	return numberKinds[kind] || nonNumberPrimitiveKinds[kind]
 }
 
-func isPrimitiveAlias(reflectType reflect.Type) bool {
-	return isPrimitive(reflectType.Kind()) && reflectType.Name() != reflectType.Kind().String()
+// isByteSlice returns true if the given Go type is a byte slice, which json.Marshal encodes as a
+// base64 string, e.g. []byte, or "type Blob []byte".
+//
+// Consistent with json.Marshal, slices of byte types that implement json.Marshaler or
+// encoding.TextMarshaler are not byte slices, and neither are byte arrays.
+func isByteSlice(typ goType) bool {
+	return typ.Kind() == reflect.Slice &&
+		typ.Elem().Kind() == reflect.Uint8 &&
+		!typ.Elem().implements(jsonMarshalerType, true) &&
+		!typ.Elem().implements(textMarshalerType, true)
+}
+
+func isPrimitiveAlias(typ goType) bool {
+	return isPrimitive(typ.Kind()) && typ.Name() != typ.Kind().String()
 }
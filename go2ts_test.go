@@ -2,11 +2,24 @@ package go2ts
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
 	"image/color"
+	"math/rand"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/skia-dev/go2ts/loader"
+	"github.com/skia-dev/go2ts/typescript"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -175,13 +188,15 @@ export namespace apple {
 		Name: string;
 		Stats: apple.YearlyYield;
 	}
-}
 
-export namespace apple {
 	export interface Orchard {
 		Farm: apple.Farm;
 		Variety: apple.Variety;
 	}
+
+	export type YearlyYield = { [key: number]: number } | null;
+
+	export type Variety = 'honeycrisp' | 'gala';
 }
 
 export interface OtherStruct {
@@ -283,10 +298,6 @@ export interface ComplexStruct {
 	AppleOrchard: apple.Orchard;
 }
 
-export namespace apple { export type YearlyYield = { [key: number]: number } | null; }
-
-export namespace apple { export type Variety = 'honeycrisp' | 'gala'; }
-
 export type Data = { [key: string]: any } | null;
 
 export type ParamSet = { [key: string]: string[] | null } | null;
@@ -299,7 +310,9 @@ export type Offset = number;
 
 export type Direction = 'up' | 'down' | 'left' | 'right';
 
-export namespace orange { export type Variety = 'bergamot' | 'clementine'; }
+export namespace orange {
+	export type Variety = 'bergamot' | 'clementine';
+}
 `
 
 	go2ts := New()
@@ -509,6 +522,28 @@ export type SomeNewName = { [key: string]: string[] };
 	assert.Equal(t, expected, b.String())
 }
 
+func TestRender_Uintptr_RenderedAsNumber(t *testing.T) {
+	type Process struct {
+		Address  uintptr
+		Labels   map[uintptr]string
+		Previous *uintptr
+	}
+
+	go2ts := New()
+	go2ts.Add(Process{})
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Process {
+	Address: number;
+	Labels: { [key: number]: string } | null;
+	Previous: number | null;
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
 func TestAdd_UnsupportedType_Panic(t *testing.T) {
 	type HasUnsupportedFieldTypes struct {
 		C complex128
@@ -637,3 +672,2375 @@ export type SomeOption = 1 | 3;
 `
 	assert.Equal(t, expected, b.String())
 }
+
+func TestAddEnum_SliceOfString_Success(t *testing.T) {
+	type Direction string
+
+	const (
+		Up   Direction = "up"
+		Down Direction = "down"
+	)
+
+	go2ts := New()
+	go2ts.AddEnum([]Direction{Up, Down}, []string{"Up", "Down"})
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export enum Direction {
+	Up = 'up',
+	Down = 'down',
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestAddEnumWithNameToNamespace_ConstObjectStyle_Success(t *testing.T) {
+	type SomeOption int
+
+	const (
+		OptionA SomeOption = 1
+		OptionB SomeOption = 3
+	)
+
+	go2ts := New()
+	go2ts.SetEnumStyle(typescript.ConstObject)
+	go2ts.AddEnumWithNameToNamespace([2]SomeOption{OptionA, OptionB}, []string{"OptionA", "OptionB"}, "Option", "Foo")
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export namespace Foo {
+	export const Option = {
+		OptionA: 1,
+		OptionB: 3,
+	} as const;
+	export type Option = (typeof Option)[keyof typeof Option];
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestAddEnum_DefinitionFoundFromStructAndEnum_EnumReplacesTypeAlias(t *testing.T) {
+	type SomeOption int
+
+	const (
+		OptionA SomeOption = 1
+		OptionB SomeOption = 3
+	)
+
+	type SomeStruct struct {
+		Choices []SomeOption
+	}
+
+	go2ts := New()
+	go2ts.Add(SomeStruct{})
+	go2ts.AddEnumWithName([2]SomeOption{OptionA, OptionB}, []string{"A", "B"}, "Option")
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface SomeStruct {
+	Choices: Option[] | null;
+}
+
+export enum Option {
+	A = 1,
+	B = 3,
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestAddEnum_InvalidInputs_Errors(t *testing.T) {
+	type Flag bool
+
+	go2ts := New()
+	go2ts.AccumulateErrors()
+	go2ts.AddEnum("not a slice", []string{"A"})
+	go2ts.AddEnum([]string{"a", "b"}, []string{"A"})
+	go2ts.AddEnum([]Flag{true}, []string{"Yes"})
+	go2ts.AddEnum([]string{"a", "b"}, []string{"A", "A"})
+	go2ts.AddEnum([]string{"a", "b", "c"}, []string{"1x", "a-b", ""})
+
+	errs, ok := go2ts.Err().(Errors)
+	require.True(t, ok)
+	require.Len(t, errs, 7)
+	assert.Equal(t, "AddEnumWithName must be supplied an array or slice, got string: not a slice", errs[0].Reason)
+	assert.Equal(t, "AddEnumWithName must be supplied one name per value, got 1 names for 2 values", errs[1].Reason)
+	assert.Equal(t, "Flag[0]", errs[2].Path)
+	assert.Equal(t, `Go Kind "bool" cannot be used in a TypeScript enum.`, errs[2].Reason)
+	assert.Equal(t, "string[1]", errs[3].Path)
+	assert.Equal(t, `Duplicate TypeScript enum member name "A".`, errs[3].Reason)
+	assert.Equal(t, "string[0]", errs[4].Path)
+	assert.Equal(t, `Invalid TypeScript enum member name "1x".`, errs[4].Reason)
+	assert.Equal(t, "string[1]", errs[5].Path)
+	assert.Equal(t, `Invalid TypeScript enum member name "a-b".`, errs[5].Reason)
+	assert.Equal(t, "string[2]", errs[6].Path)
+	assert.Equal(t, `Invalid TypeScript enum member name "".`, errs[6].Reason)
+}
+
+func TestRender_EmitTypeGuards_GuardsRenderedAfterDeclarations(t *testing.T) {
+	type Direction string
+
+	type Turtle struct {
+		Direction Direction
+		Tags      []string `json:",omitempty"`
+	}
+
+	go2ts := New()
+	go2ts.EmitTypeGuards()
+	go2ts.AddUnion([]Direction{"up", "down"})
+	go2ts.Add(Turtle{})
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Turtle {
+	Direction: Direction;
+	Tags?: string[] | null;
+}
+
+export type Direction = 'up' | 'down';
+
+export function isTurtle(x: unknown): x is Turtle {
+	if (typeof x !== 'object' || x === null || Array.isArray(x)) {
+		return false;
+	}
+	const o = x as Record<string, unknown>;
+	return (
+		isDirection(o['Direction']) &&
+		(o['Tags'] === undefined || (Array.isArray(o['Tags']) && o['Tags'].every(($v1) => typeof $v1 === 'string')) || o['Tags'] === null)
+	);
+}
+
+export function isDirection(x: unknown): x is Direction {
+	return x === 'up' || x === 'down';
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestRender_EmitTypeGuards_NamespaceNamedLikeParameter_NotShadowed(t *testing.T) {
+	type Job struct {
+		Name string
+	}
+
+	type User struct {
+		Jobs []Job
+	}
+
+	go2ts := New()
+	go2ts.EmitTypeGuards()
+	go2ts.AddToNamespaceIgnoreNil(Job{}, "v1")
+	go2ts.AddIgnoreNil(User{})
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	assert.Contains(t, b.String(), `
+export function isUser(x: unknown): x is User {
+	if (typeof x !== 'object' || x === null || Array.isArray(x)) {
+		return false;
+	}
+	const o = x as Record<string, unknown>;
+	return (
+		(Array.isArray(o['Jobs']) && o['Jobs'].every(($v1) => v1.isJob($v1)))
+	);
+}
+`)
+}
+
+func TestRender_JSONStringOption_NumbersAndBooleansRenderedAsStrings(t *testing.T) {
+	type ID int64
+
+	type Record struct {
+		ID         int64    `json:"id,string"`
+		NamedID    ID       `json:",string"`
+		Enabled    bool     `json:"enabled,omitempty,string"`
+		Ratio      *float64 `json:"ratio,string,omitempty"`
+		Name       string   `json:"name,string"`
+		Tags       []int    `json:"tags,string"`
+		Duration   Duration `json:"duration,string"`
+		NotQuoted  int64    `json:"notQuoted"`
+		DoublePtr  **int    `json:"doublePtr,string"`
+		IgnoredNil *int     `json:"ignoredNil,string" go2ts:"ignorenil"`
+	}
+
+	go2ts := New()
+	go2ts.Add(Record{})
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Record {
+	id: string;
+	NamedID: string;
+	enabled?: string;
+	ratio?: string | null;
+	name: string;
+	tags: number[] | null;
+	duration: Duration;
+	notQuoted: number;
+	doublePtr: number | null;
+	ignoredNil: string;
+}
+
+export type Duration = string;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestRender_JSONTagOptions_ParsedRegardlessOfPosition(t *testing.T) {
+	type Record struct {
+		A int64     `json:"a,omitempty,string"`
+		B int64     `json:"b,string,omitempty"`
+		C time.Time `json:"c,omitzero"`
+		D int       `json:",omitzero,omitempty"`
+		E int       `json:"e,"`
+		F int       `json:"f,omitempty,omitempy"`
+		G int       `json:"g,inline"`
+	}
+
+	go2ts := New()
+	go2ts.Add(Record{})
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Record {
+	a?: string;
+	b?: string;
+	c?: string;
+	D?: number;
+	e: number;
+	f?: number;
+	g: number;
+}
+`
+	assert.Equal(t, expected, b.String())
+
+	warnings := go2ts.Warnings()
+	require.Len(t, warnings, 2)
+	assert.Equal(t, `Record.F: Unknown json tag option "omitempy"`, warnings[0].Error())
+	assert.Equal(t, reflect.TypeOf(0), warnings[0].Type)
+	assert.Equal(t, `Record.G: Unknown json tag option "inline"`, warnings[1].Error())
+}
+
+func TestRender_JSONDashCommaTag_RenderedAsPropertyNamedDash(t *testing.T) {
+	type Person struct {
+		Skipped  int    `json:"-"`
+		Dash     int    `json:"-,"`
+		Hyphened string `json:"first-name,omitempty"`
+	}
+
+	go2ts := New()
+	go2ts.EmitTypeGuards()
+	go2ts.Add(Person{})
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	assert.Equal(t, `// DO NOT EDIT. This file is automatically generated.
+
+export interface Person {
+	'-': number;
+	'first-name'?: string;
+}
+
+export function isPerson(x: unknown): x is Person {
+	if (typeof x !== 'object' || x === null || Array.isArray(x)) {
+		return false;
+	}
+	const o = x as Record<string, unknown>;
+	return (
+		typeof o['-'] === 'number' &&
+		(o['first-name'] === undefined || typeof o['first-name'] === 'string')
+	);
+}
+`, b.String())
+}
+
+func TestRender_InvalidJSONTagNames_RenderedAsFieldName(t *testing.T) {
+	type Person struct {
+		Quote     int `json:"a'b"`
+		Backslash int `json:"a\\b,omitempty"`
+		Valid     int `json:"a b!"`
+	}
+
+	go2ts := New()
+	go2ts.Add(Person{})
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	assert.Equal(t, `// DO NOT EDIT. This file is automatically generated.
+
+export interface Person {
+	Quote: number;
+	Backslash?: number;
+	'a b!': number;
+}
+`, b.String())
+
+	warnings := go2ts.Warnings()
+	require.Len(t, warnings, 2)
+	assert.Equal(t, `Person.Quote: Invalid json tag name "a'b"`, warnings[0].Error())
+	assert.Equal(t, `Person.Backslash: Invalid json tag name "a\\b"`, warnings[1].Error())
+}
+
+func TestRender_ByteSlices_RenderedAsBase64Strings(t *testing.T) {
+	type Blob []byte
+
+	type Record struct {
+		Bytes        []byte
+		Blob         Blob
+		BlobNoNil    []byte `go2ts:"ignorenil"`
+		Array        [4]byte
+		UUIDs        []UUID // UUID implements encoding.TextMarshaler.
+		Raw          json.RawMessage
+		RawNullable  *json.RawMessage
+		SliceOfBlobs []Blob
+	}
+
+	go2ts := New()
+	go2ts.Add(Record{})
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Record {
+	Bytes: string | null;
+	Blob: Blob;
+	BlobNoNil: string;
+	Array: number[];
+	UUIDs: UUID[] | null;
+	Raw: any;
+	RawNullable: any | null;
+	SliceOfBlobs: Blob[] | null;
+}
+
+export type Blob = string | null;
+
+export type UUID = string;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestRender_MapKeys_TextMarshalersAndUnionsSupported(t *testing.T) {
+	type Mode string
+
+	type Direction string
+
+	type Record struct {
+		ByUUID      map[UUID]int      // UUID implements encoding.TextMarshaler with a value receiver.
+		ByPoint     map[Point]int     // Point implements encoding.TextMarshaler with a value receiver.
+		ByDuration  map[Duration]int  // Pointer receivers do not apply to map keys.
+		ByMode      map[Mode]int      // Not a union, thus an index signature.
+		ByDirection map[Direction]int // A union, thus a mapped type.
+	}
+
+	go2ts := New()
+	go2ts.Add(Record{})
+	go2ts.AddUnion([]Direction{"up", "down"})
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Record {
+	ByUUID: { [key: string]: number } | null;
+	ByPoint: { [key: string]: number } | null;
+	ByDuration: { [key: number]: number } | null;
+	ByMode: { [key: string]: number } | null;
+	ByDirection: { [key in Direction]?: number } | null;
+}
+
+export type Direction = 'up' | 'down';
+`
+	assert.Equal(t, expected, b.String())
+
+	// Undeclared map key types don't leak into snapshots.
+	data, err := typescript.MarshalTypeDeclarations(go2ts.TypeDeclarations())
+	require.NoError(t, err)
+	_, err = typescript.UnmarshalTypeDeclarations(data)
+	require.NoError(t, err)
+}
+
+func TestRender_MapKeys_DeclaredLater_MapsReflectDeclarations(t *testing.T) {
+	type Mode string
+
+	type Direction string
+
+	type Record struct {
+		ByMode      map[Mode]int
+		ByDirection map[Direction]int
+		Mode        Mode
+	}
+
+	go2ts := New()
+	go2ts.AddIgnoreNil(Record{})
+	go2ts.AddEnumWithName([]Direction{"up", "down"}, []string{"Up", "Down"}, "")
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	assert.Equal(t, `// DO NOT EDIT. This file is automatically generated.
+
+export interface Record {
+	ByMode: { [key: string]: number };
+	ByDirection: { [key in Direction]?: number };
+	Mode: Mode;
+}
+
+export type Mode = string;
+
+export enum Direction {
+	Up = 'up',
+	Down = 'down',
+}
+`, b.String())
+}
+
+func TestAccumulateErrors_UnsupportedTypes_AllErrorsReturnedByRender(t *testing.T) {
+	type Key struct {
+		A string
+	}
+
+	type YearlyYield map[Key]int
+
+	type Farm struct {
+		Name  string
+		Stats YearlyYield
+		Hooks []func()
+	}
+
+	type NotAUnion int
+
+	go2ts := New()
+	go2ts.AccumulateErrors()
+	go2ts.Add(Farm{})
+	go2ts.AddUnion(NotAUnion(1))
+	go2ts.AddUnion([]complex64{1})
+
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.Error(t, err)
+	assert.Empty(t, b.String())
+
+	errs, ok := err.(Errors)
+	require.True(t, ok)
+	require.Len(t, errs, 4)
+
+	assert.Equal(t, reflect.TypeOf(Key{}), errs[0].Type)
+	assert.Equal(t, "Farm.Stats.key", errs[0].Path)
+	assert.Equal(t, `Go Kind "struct" cannot be used as a TypeScript index signature parameter type.`, errs[0].Reason)
+
+	assert.Equal(t, reflect.TypeOf(func() {}), errs[1].Type)
+	assert.Equal(t, "Farm.Hooks[]", errs[1].Path)
+	assert.Equal(t, `Go Kind "func" cannot be serialized to JSON.`, errs[1].Reason)
+
+	assert.Equal(t, reflect.TypeOf(NotAUnion(1)), errs[2].Type)
+	assert.Equal(t, "NotAUnion", errs[2].Path)
+	assert.Equal(t, "AddUnionWithName must be supplied an array or slice, got int: 1", errs[2].Reason)
+
+	assert.Equal(t, reflect.TypeOf(complex64(1)), errs[3].Type)
+	assert.Equal(t, "complex64[0]", errs[3].Path)
+	assert.Equal(t, `Go Kind "complex64" cannot be used in a TypeScript union type.`, errs[3].Reason)
+
+	assert.Equal(t, `4 error(s) found:
+Farm.Stats.key: Go Kind "struct" cannot be used as a TypeScript index signature parameter type.
+Farm.Hooks[]: Go Kind "func" cannot be serialized to JSON.
+NotAUnion: AddUnionWithName must be supplied an array or slice, got int: 1
+complex64[0]: Go Kind "complex64" cannot be used in a TypeScript union type.`, err.Error())
+}
+
+func TestAccumulateErrors_NoErrors_RenderSucceeds(t *testing.T) {
+	type SomeStruct struct {
+		B string
+	}
+
+	go2ts := New()
+	go2ts.AccumulateErrors()
+	go2ts.Add(SomeStruct{})
+	require.NoError(t, go2ts.Err())
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface SomeStruct {
+	B: string;
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+// UUID implements encoding.TextMarshaler with a value receiver.
+type UUID [16]byte
+
+func (u UUID) MarshalText() ([]byte, error) { return []byte("uuid"), nil }
+
+// Duration implements encoding.TextMarshaler with a pointer receiver.
+type Duration int64
+
+func (d *Duration) MarshalText() ([]byte, error) { return []byte("1s"), nil }
+
+// BigNumber implements json.Marshaler with a pointer receiver.
+type BigNumber struct {
+	digits []byte
+}
+
+func (b *BigNumber) MarshalJSON() ([]byte, error) { return []byte("123"), nil }
+
+// Point implements both json.Marshaler and encoding.TextMarshaler.
+type Point struct {
+	X, Y int
+}
+
+func (p Point) MarshalJSON() ([]byte, error) { return json.Marshal([]int{p.X, p.Y}) }
+
+func (p Point) MarshalText() ([]byte, error) { return []byte("point"), nil }
+
+func TestRender_Marshalers_RenderedAccordingToMarshaler(t *testing.T) {
+	type Marshalers struct {
+		UUID      UUID
+		UUIDPtr   *UUID
+		Duration  Duration
+		BigNumber BigNumber
+		Point     Point
+		Time      time.Time
+		TimePtr   *time.Time
+	}
+
+	go2ts := New()
+	go2ts.SetJSONMarshalerType(Point{}, &typescript.ArrayType{ItemsType: typescript.Number})
+	go2ts.Add(Marshalers{})
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Marshalers {
+	UUID: UUID;
+	UUIDPtr: UUID | null;
+	Duration: Duration;
+	BigNumber: any;
+	Point: number[];
+	Time: string;
+	TimePtr: string | null;
+}
+
+export type UUID = string;
+
+export type Duration = string;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+// Label implements encoding.TextMarshaler with a pointer receiver.
+type Label struct {
+	A int
+}
+
+func (l *Label) MarshalText() ([]byte, error) { return []byte("label"), nil }
+
+func TestRender_PointerReceiverMarshalers_OnlyAppliedToAddressableValues(t *testing.T) {
+	// Label is declared as an interface for the ByName map values before being found in the other,
+	// addressable fields.
+	type Labels struct {
+		ByName     map[string]Label
+		Field      Label
+		Ptr        *Label
+		List       []Label
+		Numbers    []BigNumber
+		NumberByID map[string]BigNumber
+	}
+
+	go2ts := New()
+	go2ts.Add(Labels{})
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Label {
+	A: number;
+}
+
+export interface BigNumber {
+}
+
+export interface Labels {
+	ByName: { [key: string]: Label } | null;
+	Field: string;
+	Ptr: string | null;
+	List: string[] | null;
+	Numbers: any[] | null;
+	NumberByID: { [key: string]: BigNumber } | null;
+}
+`
+	assert.Equal(t, expected, b.String())
+
+	// Consistent with json.Marshal.
+	data, err := json.Marshal(map[string]Label{"a": {A: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":{"A":1}}`, string(data))
+}
+
+func TestRender_AddMarshaler_RenderedAsTypeAlias(t *testing.T) {
+	go2ts := New()
+	go2ts.Add(Point{})
+	go2ts.Add(time.Time{})
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export type Point = any;
+
+export type Time = string;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestRender_CustomTypes_RenderedAsCustomType(t *testing.T) {
+	type Decimal struct {
+		value []byte
+	}
+
+	type NullString struct {
+		String string
+		Valid  bool
+	}
+
+	type Money int64
+
+	type GeoPoint struct {
+		Lat, Lng float64
+	}
+
+	type Payment struct {
+		Amount      Decimal
+		Memo        NullString
+		MemoPtr     *NullString `go2ts:"ignorenil"`
+		Fee         Money
+		Location    GeoPoint
+		Time        time.Time
+		BigNumber   BigNumber
+		UUID        UUID
+		Destination GeoPoint
+	}
+
+	go2ts := New()
+	go2ts.AddCustomType(Decimal{}, typescript.String)
+	go2ts.AddCustomType(reflect.TypeOf(NullString{}), &typescript.UnionType{Types: []typescript.Type{typescript.String, typescript.Null}})
+	go2ts.AddCustomType(Money(0), typescript.String)
+	go2ts.AddCustomTypeFunc(func(t reflect.Type) bool {
+		return t.Kind() == reflect.Struct && t.NumField() == 2 && t.Field(0).Type.Kind() == reflect.Float64
+	}, &typescript.ArrayType{ItemsType: typescript.Number})
+	go2ts.AddCustomType(time.Time{}, typescript.Number) // Overrides the default mapping.
+	go2ts.AddCustomType(BigNumber{}, typescript.String) // Overrides the default mapping.
+	go2ts.Add(Payment{})
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Payment {
+	Amount: string;
+	Memo: string | null;
+	MemoPtr: string | null;
+	Fee: Money;
+	Location: number[];
+	Time: number;
+	BigNumber: string;
+	UUID: UUID;
+	Destination: number[];
+}
+
+export type Money = string;
+
+export type UUID = string;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestAddCustomType_LaterCustomTypesTakePrecedence(t *testing.T) {
+	type Decimal struct {
+		value []byte
+	}
+
+	go2ts := New()
+	go2ts.AddCustomTypeFunc(func(t reflect.Type) bool { return true }, typescript.Number)
+	go2ts.AddCustomType(Decimal{}, typescript.String)
+	go2ts.AddWithName(Decimal{}, "Amount")
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export type Amount = string;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+// loadSourcePackage type-checks the given Go source code, which must declare package "source".
+func loadSourcePackage(t *testing.T, src string) *types.Package {
+	pkg, _ := loadSourcePackageWithSyntax(t, src)
+	return pkg
+}
+
+// loadSourcePackageWithSyntax is like loadSourcePackage, but it also returns the package's syntax
+// tree, suitable for Go2TS.UsePackage().
+func loadSourcePackageWithSyntax(t *testing.T, src string) (*types.Package, []*ast.File) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "source.go", src, parser.ParseComments)
+	require.NoError(t, err)
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("example.com/source", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+	return pkg, []*ast.File{file}
+}
+
+func TestRender_SourceTypes_SameAsReflection(t *testing.T) {
+	const src = `package source
+
+import "time"
+
+type Mode string
+
+type Modes = []Mode
+
+type Inner struct {
+	InnerField string
+}
+
+type Tags map[string][]string
+
+type Version int
+
+func (v Version) MarshalText() ([]byte, error) { return nil, nil }
+
+type Outer struct {
+	*Inner
+	Name      string    ` + "`json:\"name\"`" + `
+	Optional  int       ` + "`json:\",omitempty\"`" + `
+	Skipped   string    ` + "`json:\"-\"`" + `
+	Time      time.Time
+	Mode      Mode
+	Modes     Modes
+	Tags      Tags
+	TagsNoNil Tags ` + "`go2ts:\"ignorenil\"`" + `
+	Version   Version
+	Bytes     []byte
+	Array     [2]bool
+	Any       interface{}
+	Next      *Outer
+	Anonymous struct{ A float64 }
+	unexported bool
+}
+`
+
+	type Mode string
+
+	type Modes = []Mode
+
+	type Inner struct {
+		InnerField string
+	}
+
+	type Tags map[string][]string
+
+	type Outer struct {
+		*Inner
+		Name       string `json:"name"`
+		Optional   int    `json:",omitempty"`
+		Skipped    string `json:"-"`
+		Time       time.Time
+		Mode       Mode
+		Modes      Modes
+		Tags       Tags
+		TagsNoNil  Tags     `go2ts:"ignorenil"`
+		Version    Duration // Implements encoding.TextMarshaler, just like source.Version.
+		Bytes      []byte
+		Array      [2]bool
+		Any        interface{}
+		Next       *Outer
+		Anonymous  struct{ A float64 }
+		unexported bool
+	}
+
+	const expected = `// DO NOT EDIT. This file is automatically generated.
+
+export interface Anonymous1 {
+	A: number;
+}
+
+export interface Outer {
+	name: string;
+	Optional?: number;
+	Time: string;
+	Mode: Mode;
+	Modes: Mode[] | null;
+	Tags: Tags;
+	TagsNoNil: Tags;
+	Version: %s;
+	Bytes: string | null;
+	Array: boolean[];
+	Any: any;
+	Next: Outer | null;
+	Anonymous: Anonymous1;
+	InnerField?: string;
+}
+
+export type Mode = string;
+
+export type Tags = { [key: string]: string[] | null } | null;
+
+export type %s = string;
+`
+
+	reflectionGo2TS := New()
+	reflectionGo2TS.Add(Outer{})
+	var reflectionOutput bytes.Buffer
+	require.NoError(t, reflectionGo2TS.Render(&reflectionOutput))
+	assert.Equal(t, fmt.Sprintf(expected, "Duration", "Duration"), reflectionOutput.String())
+
+	pkg := loadSourcePackage(t, src)
+	sourceGo2TS := New()
+	sourceGo2TS.Add(pkg.Scope().Lookup("Outer").Type())
+	var sourceOutput bytes.Buffer
+	require.NoError(t, sourceGo2TS.Render(&sourceOutput))
+	assert.Equal(t, fmt.Sprintf(expected, "Version", "Version"), sourceOutput.String())
+}
+
+func TestAddCustomType_SourceTypes_MatchedByPackagePathAndName(t *testing.T) {
+	const src = `package source
+
+type Decimal struct {
+	Value []byte
+}
+
+type Payment struct {
+	Amount Decimal
+}
+`
+
+	pkg := loadSourcePackage(t, src)
+	go2ts := New()
+	go2ts.AddCustomType(pkg.Scope().Lookup("Decimal").Type(), typescript.String)
+	go2ts.AddCustomTypeFunc(func(reflect.Type) bool { return true }, typescript.Number) // Ignored.
+	go2ts.Add(pkg.Scope().Lookup("Payment").Type())
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Payment {
+	Amount: string;
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestAccumulateErrors_SourceTypes_ErrorHasNoReflectType(t *testing.T) {
+	const src = `package source
+
+type Farm struct {
+	Hooks map[string]chan int
+}
+`
+
+	pkg := loadSourcePackage(t, src)
+	go2ts := New()
+	go2ts.AccumulateErrors()
+	go2ts.Add(pkg.Scope().Lookup("Farm").Type())
+	errs, ok := go2ts.Err().(Errors)
+	require.True(t, ok)
+	require.Len(t, errs, 1)
+	assert.Nil(t, errs[0].Type)
+	assert.Equal(t, "Farm.Hooks.value", errs[0].Path)
+	assert.Equal(t, `Go Kind "chan" cannot be serialized to JSON.`, errs[0].Reason)
+}
+
+func TestAccumulateErrors_UnsupportedSourceTypeKind_Error(t *testing.T) {
+	// Untyped nil has no corresponding reflect.Kind, i.e. its Kind is reflect.Invalid.
+	pkg := types.NewPackage("example.com/source", "source")
+	fields := []*types.Var{types.NewField(token.NoPos, pkg, "Nothing", types.Typ[types.UntypedNil], false)}
+	farm := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "Farm", nil), types.NewStruct(fields, nil), nil)
+
+	go2ts := New()
+	go2ts.AccumulateErrors()
+	go2ts.Add(farm)
+	errs, ok := go2ts.Err().(Errors)
+	require.True(t, ok)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Farm.Nothing", errs[0].Path)
+	assert.Equal(t, `Go Kind "invalid" is not supported.`, errs[0].Reason)
+}
+
+func TestAddConstUnion_SourceTypes_Success(t *testing.T) {
+	const src = `package source
+
+type Direction string
+
+const (
+	Up    Direction = "up"
+	Down  Direction = "down"
+	left  Direction = "left"
+	Right Direction = "right"
+
+	Default = Up
+)
+
+type Level int
+
+const (
+	_ Level = iota
+	Low
+	_
+	High
+)
+
+type Ratio float64
+
+const Half Ratio = 0.5
+
+const Untyped = "not a Direction"
+
+var NotAConstant Direction = "nowhere"
+
+type Mode string
+
+type Turtle struct {
+	Direction Direction
+	Level     Level
+	Mode      Mode
+}
+`
+
+	pkg := loadSourcePackage(t, src)
+	go2ts := New()
+	go2ts.AddConstUnion(pkg.Scope().Lookup("Direction").Type())
+	go2ts.AddConstUnionWithNameToNamespace(pkg.Scope().Lookup("Level").Type(), "TurtleLevel", "turtle")
+	go2ts.AddConstUnionToNamespace(pkg.Scope().Lookup("Ratio").Type(), "turtle")
+	go2ts.Add(pkg.Scope().Lookup("Turtle").Type())
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Turtle {
+	Direction: Direction;
+	Level: turtle.TurtleLevel;
+	Mode: Mode;
+}
+
+export type Direction = 'up' | 'down' | 'left' | 'right';
+
+export namespace turtle {
+	export type TurtleLevel = 1 | 3;
+
+	export type Ratio = 0.5;
+}
+
+export type Mode = string;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestDiscoverConstUnions_SourceTypes_Success(t *testing.T) {
+	const src = `package source
+
+type Direction string
+
+const (
+	Up   Direction = "up"
+	Down Direction = "down"
+)
+
+type Mode string
+
+type Enabled bool
+
+const On Enabled = true
+
+type Turtle struct {
+	Direction Direction
+	Mode      Mode
+	Enabled   Enabled
+}
+`
+
+	pkg := loadSourcePackage(t, src)
+	go2ts := New()
+	go2ts.DiscoverConstUnions()
+	go2ts.Add(pkg.Scope().Lookup("Turtle").Type())
+	go2ts.Add(pkg.Scope().Lookup("Direction").Type())
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Turtle {
+	Direction: Direction;
+	Mode: Mode;
+	Enabled: Enabled;
+}
+
+export type Direction = 'up' | 'down';
+
+export type Mode = string;
+
+export type Enabled = true;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestAddConstUnion_ReflectionType_PackageLoadedFromSource(t *testing.T) {
+	go2ts := New()
+	go2ts.SetPackageLoader(loader.Load)
+	go2ts.AddConstUnion(time.Weekday(0))
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export type Weekday = 0 | 1 | 2 | 3 | 4 | 5 | 6;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestAddConstUnion_ReflectionTypeWithoutPackageLoader_Error(t *testing.T) {
+	go2ts := New()
+	go2ts.AccumulateErrors()
+	go2ts.AddConstUnion(time.Weekday(0))
+
+	errs, ok := go2ts.Err().(Errors)
+	require.True(t, ok)
+	require.Len(t, errs, 1)
+	assert.Equal(t, `Failed to load package "time": no package loader, see SetPackageLoader()`, errs[0].Reason)
+}
+
+func TestAddConstUnion_InvalidTypes_Errors(t *testing.T) {
+	type LocalType string
+
+	const src = `package source
+
+type NoConstants string
+
+type Struct struct{}
+`
+
+	pkg := loadSourcePackage(t, src)
+	go2ts := New()
+	go2ts.SetPackageLoader(loader.Load)
+	go2ts.AccumulateErrors()
+	go2ts.AddConstUnion(pkg.Scope().Lookup("NoConstants").Type())
+	go2ts.AddConstUnion(pkg.Scope().Lookup("Struct").Type())
+	go2ts.AddConstUnion(LocalType(""))
+	go2ts.AddConstUnion(Duration(0)) // Implements encoding.TextMarshaler.
+
+	errs, ok := go2ts.Err().(Errors)
+	require.True(t, ok)
+	require.Len(t, errs, 4)
+	assert.Equal(t, `No constants of Go type source.NoConstants found in package "example.com/source"`, errs[0].Reason)
+	assert.Equal(t, "Go type source.Struct cannot be used in a TypeScript union type; it must be a named boolean, number or string type", errs[1].Reason)
+	assert.Equal(t, `Go type go2ts.LocalType is not declared at the package level of package "github.com/skia-dev/go2ts"`, errs[2].Reason)
+	assert.Equal(t, "Go type go2ts.Duration has a custom type mapping, so its constants do not match its JSON representation", errs[3].Reason)
+}
+
+func TestEmitDocComments_SourceTypes_Success(t *testing.T) {
+	const src = `package source
+
+// Mode is the mode of operation.
+type Mode string
+
+type (
+	// Point is a point in the plane.
+	//
+	// The origin is at the top left.
+	//
+	//go2ts:export
+	Point struct {
+		// X is the horizontal coordinate.
+		X int
+		Y int // Y is the vertical coordinate.
+	}
+
+	Undocumented struct {
+		Mode Mode
+	}
+)
+
+// Base is embedded by Shape.
+type Base struct {
+	// ID is the unique ID.
+	ID string
+}
+
+// Shape has a position.
+type Shape struct {
+	Base
+
+	// Position is where the shape is.
+	Position Point
+
+	// Mode is documented in both the field and the type.
+	Mode Mode
+
+	// Other has no documentation of its own.
+	Other Undocumented
+}
+`
+
+	pkg, syntax := loadSourcePackageWithSyntax(t, src)
+	go2ts := New()
+	go2ts.EmitDocComments()
+	go2ts.UsePackage(pkg, syntax)
+	go2ts.Add(pkg.Scope().Lookup("Shape").Type())
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+/**
+ * Point is a point in the plane.
+ *
+ * The origin is at the top left.
+ */
+export interface Point {
+	/** X is the horizontal coordinate. */
+	X: number;
+	/** Y is the vertical coordinate. */
+	Y: number;
+}
+
+export interface Undocumented {
+	Mode: Mode;
+}
+
+/** Shape has a position. */
+export interface Shape {
+	/** Position is where the shape is. */
+	Position: Point;
+	/** Mode is documented in both the field and the type. */
+	Mode: Mode;
+	/** Other has no documentation of its own. */
+	Other: Undocumented;
+	/** ID is the unique ID. */
+	ID: string;
+}
+
+/** Mode is the mode of operation. */
+export type Mode = string;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestEmitDocComments_NotCalled_NoDocComments(t *testing.T) {
+	const src = `package source
+
+// Point is a point in the plane.
+type Point struct {
+	// X is the horizontal coordinate.
+	X int
+}
+`
+
+	pkg, syntax := loadSourcePackageWithSyntax(t, src)
+	go2ts := New()
+	go2ts.UsePackage(pkg, syntax)
+	go2ts.Add(pkg.Scope().Lookup("Point").Type())
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Point {
+	X: number;
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestEmitDocComments_ReflectionType_PackageLoadedFromSource(t *testing.T) {
+	type LocalType struct {
+		// Literal has no doc comment because LocalType is declared inside a function.
+		Literal typescript.LiteralType
+	}
+
+	go2ts := New()
+	go2ts.SetPackageLoader(loader.Load)
+	go2ts.EmitDocComments()
+	go2ts.Add(LocalType{})
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+/**
+ * LiteralType represents a TypeScript literal type such as "hello", 123, true, etc.
+ *
+ * See https://www.typescriptlang.org/docs/handbook/literal-types.html.
+ */
+export interface LiteralType {
+	BasicType: BasicType;
+	Literal: string;
+}
+
+export interface LocalType {
+	Literal: LiteralType;
+}
+
+/**
+ * BasicType represents a TypeScript basic type supported by Go2TS.
+ *
+ * The "null" and "any" types are represented as basic types for simplicity.
+ */
+export type BasicType = string;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+const nameCollisionsSrc = `package source
+
+type Config struct {
+	Name string
+}
+
+type Mode string
+
+type Settings struct {
+	Config Config
+	Mode   Mode
+}
+`
+
+func TestRender_NameCollisions_PrefixedWithPackageName(t *testing.T) {
+	type Config struct {
+		Verbose bool
+	}
+
+	type Mode string
+
+	type Settings struct {
+		Config Config
+		Mode   Mode
+	}
+
+	pkg := loadSourcePackage(t, nameCollisionsSrc)
+	go2ts := New()
+	go2ts.Add(Settings{})
+	go2ts.Add(pkg.Scope().Lookup("Settings").Type())
+	go2ts.AddUnion([]Mode{"fast"})
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Config {
+	Verbose: boolean;
+}
+
+export interface Settings {
+	Config: Config;
+	Mode: Mode;
+}
+
+export interface SourceConfig {
+	Name: string;
+}
+
+export interface SourceSettings {
+	Config: SourceConfig;
+	Mode: SourceMode;
+}
+
+export type Mode = 'fast';
+
+export type SourceMode = string;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestRender_NameCollisionsInDifferentNamespaces_NotRenamed(t *testing.T) {
+	type Config struct {
+		Verbose bool
+	}
+
+	pkg := loadSourcePackage(t, nameCollisionsSrc)
+	go2ts := New()
+	go2ts.SetNameCollisionStrategy(FailOnNameCollision)
+	go2ts.AddToNamespace(Config{}, "local")
+	go2ts.AddToNamespace(pkg.Scope().Lookup("Config").Type(), "source")
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export namespace local {
+	export interface Config {
+		Verbose: boolean;
+	}
+}
+
+export namespace source {
+	export interface Config {
+		Name: string;
+	}
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestSetNameCollisionStrategy_FailOnNameCollision_Errors(t *testing.T) {
+	type Mode string
+
+	pkg := loadSourcePackage(t, nameCollisionsSrc)
+	go2ts := New()
+	go2ts.SetNameCollisionStrategy(FailOnNameCollision)
+	go2ts.AccumulateErrors()
+	go2ts.AddUnion([]Mode{"fast"})
+	go2ts.Add(pkg.Scope().Lookup("Settings").Type())
+	errs, ok := go2ts.Err().(Errors)
+	require.True(t, ok)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "Settings.Mode", errs[0].Path)
+	assert.Equal(t, `TypeScript type "Mode" for Go type source.Mode is already used by Go type go2ts.Mode.`, errs[0].Reason)
+}
+
+func TestSetNameCollisionFunc_RenamesCollidingTypes(t *testing.T) {
+	type Config struct {
+		Verbose bool
+	}
+
+	pkg := loadSourcePackage(t, nameCollisionsSrc)
+	go2ts := New()
+	var pkgPaths []string
+	go2ts.SetNameCollisionFunc(func(pkgPath, identifier string) string {
+		pkgPaths = append(pkgPaths, pkgPath)
+		return "Remote" + identifier
+	})
+	go2ts.Add(Config{})
+	go2ts.Add(pkg.Scope().Lookup("Config").Type())
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Config {
+	Verbose: boolean;
+}
+
+export interface RemoteConfig {
+	Name: string;
+}
+`
+	assert.Equal(t, expected, b.String())
+	assert.Equal(t, []string{"example.com/source"}, pkgPaths)
+}
+
+func TestSetNameCollisionFunc_ReturnsCollidingName_Panics(t *testing.T) {
+	type Config struct {
+		Verbose bool
+	}
+
+	pkg := loadSourcePackage(t, nameCollisionsSrc)
+	go2ts := New()
+	go2ts.SetNameCollisionFunc(func(pkgPath, identifier string) string { return identifier })
+	go2ts.Add(Config{})
+	assert.PanicsWithValue(t, `TypeScript type "Config" returned by the name collision function for Go type source.Config is already used by Go type go2ts.Config.`, func() {
+		go2ts.Add(pkg.Scope().Lookup("Config").Type())
+	})
+}
+
+type Page[T any] struct {
+	Items []T
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+func TestRender_InstantiatedGenericTypes_RenderedWithSanitizedNames(t *testing.T) {
+	type Item struct {
+		Name string
+	}
+
+	type Catalog struct {
+		Items Page[Item]
+		Pairs []Pair[string, *Item]
+	}
+
+	go2ts := New()
+	go2ts.EmitGenerics() // Ignored for types obtained via reflection.
+	go2ts.Add(Catalog{})
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Item {
+	Name: string;
+}
+
+export interface PageItem {
+	Items: Item[] | null;
+}
+
+export interface PairStringItem {
+	Key: string;
+	Value: Item | null;
+}
+
+export interface Catalog {
+	Items: PageItem;
+	Pairs: PairStringItem[] | null;
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+const genericsSrc = `package source
+
+type Item struct {
+	Name string
+}
+
+type Page[T any] struct {
+	Items []T
+	Next  *Page[T]
+}
+
+type List[T any] []T
+
+type Index[K comparable, V any] map[K]V
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+type Catalog struct {
+	Items Page[Item]
+	Names List[string]
+	Pairs []Pair[string, List[Item]]
+	Index Index[string, Item]
+}
+`
+
+func TestEmitGenerics_SourceTypes_RenderedAsGenericTypes(t *testing.T) {
+	pkg := loadSourcePackage(t, genericsSrc)
+	go2ts := New()
+	go2ts.EmitGenerics()
+	go2ts.Add(pkg.Scope().Lookup("Catalog").Type())
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Page<T> {
+	Items: T[] | null;
+	Next: Page<T> | null;
+}
+
+export interface Item {
+	Name: string;
+}
+
+export interface Pair<K, V> {
+	Key: K;
+	Value: V;
+}
+
+export interface Catalog {
+	Items: Page<Item>;
+	Names: List<string>;
+	Pairs: Pair<string, List<Item>>[] | null;
+	Index: Index<string, Item>;
+}
+
+export type List<T> = T[] | null;
+
+export type Index<K, V> = { [key: string]: V } | null;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestEmitGenerics_NotCalled_SourceTypesRenderedWithSanitizedNames(t *testing.T) {
+	pkg := loadSourcePackage(t, genericsSrc)
+	go2ts := New()
+	go2ts.Add(pkg.Scope().Lookup("Catalog").Type())
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Item {
+	Name: string;
+}
+
+export interface PageItem {
+	Items: Item[] | null;
+	Next: PageItem | null;
+}
+
+export interface PairStringListItem {
+	Key: string;
+	Value: ListItem;
+}
+
+export interface Catalog {
+	Items: PageItem;
+	Names: ListString;
+	Pairs: PairStringListItem[] | null;
+	Index: IndexStringItem;
+}
+
+export type ListString = string[] | null;
+
+export type ListItem = Item[] | null;
+
+export type IndexStringItem = { [key: string]: Item } | null;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestEmitGenerics_InstantiatedTypeAdded_GenericTypeDeclared(t *testing.T) {
+	pkg := loadSourcePackage(t, genericsSrc)
+	catalog := pkg.Scope().Lookup("Catalog").Type().Underlying().(*types.Struct)
+	go2ts := New()
+	go2ts.EmitGenerics()
+	go2ts.Add(catalog.Field(1).Type()) // List[string].
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export type List<T> = T[] | null;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestAddToNamespace_NestedNamespaces_GroupedInNamespaceBlocks(t *testing.T) {
+	type JobID string
+
+	type Job struct {
+		ID JobID
+	}
+
+	type User struct {
+		Name string
+		Jobs []Job
+	}
+
+	go2ts := New()
+	go2ts.EmitTypeGuards()
+	go2ts.AddToNamespaceIgnoreNil(Job{}, "api.v1")
+	go2ts.AddToNamespaceIgnoreNil(User{}, "api")
+	var b bytes.Buffer
+	err := go2ts.Render(&b)
+	require.NoError(t, err)
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export namespace api {
+	export namespace v1 {
+		export interface Job {
+			ID: api.v1.JobID;
+		}
+
+		export type JobID = string;
+
+		export function isJob(x: unknown): x is api.v1.Job {
+			if (typeof x !== 'object' || x === null || Array.isArray(x)) {
+				return false;
+			}
+			const o = x as Record<string, unknown>;
+			return (
+				api.v1.isJobID(o['ID'])
+			);
+		}
+
+		export function isJobID(x: unknown): x is api.v1.JobID {
+			return typeof x === 'string';
+		}
+	}
+
+	export interface User {
+		Name: string;
+		Jobs: api.v1.Job[];
+	}
+
+	export function isUser(x: unknown): x is api.User {
+		if (typeof x !== 'object' || x === null || Array.isArray(x)) {
+			return false;
+		}
+		const o = x as Record<string, unknown>;
+		return (
+			typeof o['Name'] === 'string' &&
+			(Array.isArray(o['Jobs']) && o['Jobs'].every(($v1) => api.v1.isJob($v1)))
+		);
+	}
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestRenderModules_GroupByPackage_OneModulePerPackage(t *testing.T) {
+	type Palette struct {
+		Colors []color.Alpha
+		Meta   struct {
+			Owner string
+		}
+	}
+
+	go2ts := New()
+	go2ts.AddIgnoreNil(Palette{})
+	dir := t.TempDir()
+	require.NoError(t, go2ts.RenderModules(dir, GroupByPackage))
+
+	contents, err := os.ReadFile(filepath.Join(dir, "github.com", "skia-dev", "go2ts.ts"))
+	require.NoError(t, err)
+	assert.Equal(t, `// DO NOT EDIT. This file is automatically generated.
+
+import type { Anonymous1 } from '../../anonymous';
+import type { Alpha } from '../../image/color';
+
+export interface Palette {
+	Colors: Alpha[];
+	Meta: Anonymous1;
+}
+`, string(contents))
+
+	contents, err = os.ReadFile(filepath.Join(dir, "image", "color.ts"))
+	require.NoError(t, err)
+	assert.Equal(t, `// DO NOT EDIT. This file is automatically generated.
+
+export interface Alpha {
+	A: number;
+}
+`, string(contents))
+
+	contents, err = os.ReadFile(filepath.Join(dir, "anonymous.ts"))
+	require.NoError(t, err)
+	assert.Equal(t, `// DO NOT EDIT. This file is automatically generated.
+
+export interface Anonymous1 {
+	Owner: string;
+}
+`, string(contents))
+}
+
+func TestModules_GroupByNamespace_OneModulePerNamespace(t *testing.T) {
+	type JobID string
+
+	type Job struct {
+		ID JobID
+	}
+
+	type User struct {
+		Jobs []Job
+	}
+
+	go2ts := New()
+	go2ts.EmitTypeGuards()
+	go2ts.AddToNamespaceIgnoreNil(Job{}, "api.v1")
+	go2ts.AddIgnoreNil(User{})
+	modules, err := go2ts.Modules(GroupByNamespace)
+	require.NoError(t, err)
+	require.Len(t, modules, 2)
+	assert.Equal(t, "api/v1", modules[0].Path)
+	assert.Equal(t, "index", modules[1].Path)
+	assert.Equal(t, `import type { Job } from './api/v1';
+import { isJob } from './api/v1';
+
+export interface User {
+	Jobs: Job[];
+}
+
+export function isUser(x: unknown): x is User {
+	if (typeof x !== 'object' || x === null || Array.isArray(x)) {
+		return false;
+	}
+	const o = x as Record<string, unknown>;
+	return (
+		(Array.isArray(o['Jobs']) && o['Jobs'].every(($v1) => isJob($v1)))
+	);
+}`, modules[1].ToTypeScript())
+}
+
+func TestModules_SameIdentifierInSameModule_Error(t *testing.T) {
+	type Config struct {
+		Verbose bool
+	}
+
+	type Settings struct {
+		Name string
+	}
+
+	go2ts := New()
+	go2ts.AddToNamespace(Config{}, "local")
+	go2ts.AddWithNameToNamespace(Settings{}, "Config", "remote")
+	_, err := go2ts.Modules(GroupByNamespace)
+	require.NoError(t, err)
+	_, err = go2ts.Modules(GroupByPackage)
+	require.EqualError(t, err, `TypeScript types "local.Config" and "remote.Config" cannot both be declared as "Config" in module "go2ts"`)
+}
+
+func TestPackageModulePaths_RelativeToCommonParentDirectory(t *testing.T) {
+	paths := packageModulePaths(map[typescript.TypeDeclaration]string{
+		&typescript.InterfaceDeclaration{Identifier: "A"}: "github.com/example/api",
+		&typescript.InterfaceDeclaration{Identifier: "B"}: "github.com/example/api/v1",
+		&typescript.InterfaceDeclaration{Identifier: "C"}: "github.com/example/jobs",
+		&typescript.InterfaceDeclaration{Identifier: "D"}: "",
+	})
+	assert.Equal(t, map[string]string{
+		"github.com/example/api":    "api",
+		"github.com/example/api/v1": "api/v1",
+		"github.com/example/jobs":   "jobs",
+		"":                          "anonymous",
+	}, paths)
+}
+
+func TestSetSortOrder_SortByQualifiedName_IndependentOfAddOrder(t *testing.T) {
+	type Mode string
+
+	type Zebra struct {
+		Mode Mode
+	}
+
+	type Aardvark struct {
+		Zebra Zebra
+	}
+
+	render := func(values ...interface{}) string {
+		go2ts := New()
+		go2ts.SetSortOrder(SortByQualifiedName)
+		go2ts.AddToNamespace(Mode(""), "modes")
+		for _, v := range values {
+			go2ts.Add(v)
+		}
+		var b bytes.Buffer
+		require.NoError(t, go2ts.Render(&b))
+		return b.String()
+	}
+
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Aardvark {
+	Zebra: Zebra;
+}
+
+export interface Zebra {
+	Mode: modes.Mode;
+}
+
+export namespace modes {
+	export type Mode = string;
+}
+`
+	assert.Equal(t, expected, render(Aardvark{}, Zebra{}))
+	assert.Equal(t, expected, render(Zebra{}, Aardvark{}))
+}
+
+func TestSetSortOrder_NameCollisions_ResolvedIndependentlyOfAddOrder(t *testing.T) {
+	type Config struct {
+		Verbose bool
+	}
+
+	type Mode string
+
+	type Settings struct {
+		Config Config
+		Mode   Mode
+	}
+
+	pkg := loadSourcePackage(t, nameCollisionsSrc)
+	render := func(sourceFirst bool) string {
+		go2ts := New()
+		go2ts.SetSortOrder(SortByQualifiedName)
+		if sourceFirst {
+			go2ts.Add(pkg.Scope().Lookup("Settings").Type())
+		}
+		go2ts.Add(Settings{})
+		go2ts.AddEnumWithName([]Mode{"fast"}, []string{"Fast"}, "")
+		if !sourceFirst {
+			go2ts.Add(pkg.Scope().Lookup("Settings").Type())
+		}
+		var b bytes.Buffer
+		require.NoError(t, go2ts.Render(&b))
+		return b.String()
+	}
+
+	// example.com/source sorts before github.com/skia-dev/go2ts, thus its types keep their names.
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Config {
+	Name: string;
+}
+
+export interface Go2tsConfig {
+	Verbose: boolean;
+}
+
+export enum Go2tsMode {
+	Fast = 'fast',
+}
+
+export interface Go2tsSettings {
+	Config: Go2tsConfig;
+	Mode: Go2tsMode;
+}
+
+export type Mode = string;
+
+export interface Settings {
+	Config: Config;
+	Mode: Mode;
+}
+`
+	assert.Equal(t, expected, render(true))
+	assert.Equal(t, expected, render(false))
+}
+
+func TestSetSortOrder_NameCollisionsInSamePackage_ResolvedIndependentlyOfAddOrder(t *testing.T) {
+	type Alpha struct {
+		A int
+	}
+
+	type Beta struct {
+		B string
+	}
+
+	render := func(alphaFirst bool) string {
+		go2ts := New()
+		go2ts.SetSortOrder(SortByQualifiedName)
+		if alphaFirst {
+			go2ts.AddWithName(Alpha{}, "Config")
+		}
+		go2ts.AddWithName(Beta{}, "Config")
+		if !alphaFirst {
+			go2ts.AddWithName(Alpha{}, "Config")
+		}
+		var b bytes.Buffer
+		require.NoError(t, go2ts.Render(&b))
+		return b.String()
+	}
+
+	// Both Go types are in the same package, thus go2ts.Alpha keeps the name as it sorts first.
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Config {
+	A: number;
+}
+
+export interface Go2tsConfig {
+	B: string;
+}
+`
+	assert.Equal(t, expected, render(true))
+	assert.Equal(t, expected, render(false))
+}
+
+func TestTypeDeclarations_SortOrderSet_RenamesOnlyAfterTypesAdded(t *testing.T) {
+	type Alpha struct {
+		A int
+	}
+
+	type Beta struct {
+		B string
+	}
+
+	go2ts := New()
+	go2ts.SetSortOrder(SortByQualifiedName)
+	go2ts.AddWithName(Beta{}, "Config")
+	typeDeclarations := go2ts.TypeDeclarations()
+	require.Len(t, typeDeclarations, 1)
+	assert.Equal(t, "Config", typeDeclarations[0].QualifiedName())
+
+	// Reading the type declarations again doesn't rename them.
+	assert.Equal(t, typeDeclarations, go2ts.TypeDeclarations())
+
+	// Adding a Go type that sorts first renames the type declarations returned earlier.
+	go2ts.AddWithName(Alpha{}, "Config")
+	typeDeclarations = go2ts.TypeDeclarations()
+	require.Len(t, typeDeclarations, 2)
+	assert.Equal(t, "Config", typeDeclarations[0].QualifiedName())
+	assert.Equal(t, "Go2tsConfig", typeDeclarations[1].QualifiedName())
+}
+
+func TestSetSortOrder_AnonymousStructs_NumberedIndependentlyOfAddOrder(t *testing.T) {
+	render := func(values ...interface{}) string {
+		go2ts := New()
+		go2ts.SetSortOrder(SortByQualifiedName)
+		for _, v := range values {
+			go2ts.Add(v)
+		}
+		var b bytes.Buffer
+		require.NoError(t, go2ts.Render(&b))
+		return b.String()
+	}
+
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+export interface Anonymous1 {
+	A: number;
+}
+
+export interface Anonymous2 {
+	B: string;
+}
+`
+	assert.Equal(t, expected, render(struct{ A int }{}, struct{ B string }{}))
+	assert.Equal(t, expected, render(struct{ B string }{}, struct{ A int }{}))
+}
+
+func TestSetSortOrder_SortByPackage_SortedByPackageThenName(t *testing.T) {
+	type Zebra struct {
+		Name string
+	}
+
+	type Aardvark struct {
+		Name string
+	}
+
+	pkg := loadSourcePackage(t, nameCollisionsSrc)
+	go2ts := New()
+	go2ts.SetSortOrder(SortByPackage)
+	go2ts.Add(Zebra{})
+	go2ts.Add(struct{ Anonymous bool }{})
+	go2ts.Add(pkg.Scope().Lookup("Settings").Type())
+	go2ts.Add(Aardvark{})
+
+	var qualifiedNames []string
+	for _, typeDeclaration := range go2ts.TypeDeclarations() {
+		qualifiedNames = append(qualifiedNames, typeDeclaration.QualifiedName())
+	}
+	// Anonymous structs first, then example.com/source, then github.com/skia-dev/go2ts.
+	assert.Equal(t, []string{"Anonymous1", "Config", "Mode", "Settings", "Aardvark", "Zebra"}, qualifiedNames)
+}
+
+func TestSetRenderOptions_QuotesInStringLiterals_Escaped(t *testing.T) {
+	type Q string
+
+	render := func(quotes typescript.QuoteStyle) string {
+		go2ts := New()
+		go2ts.AddUnion([]Q{`say "hi"`, "it's", `back\slash`, "new\nline"})
+		go2ts.SetRenderOptions(typescript.RenderOptions{Quotes: quotes})
+		var b bytes.Buffer
+		require.NoError(t, go2ts.Render(&b))
+		return b.String()
+	}
+
+	assert.Equal(t, `// DO NOT EDIT. This file is automatically generated.
+
+export type Q = "say \"hi\"" | "it's" | "back\\slash" | "new\nline";
+`, render(typescript.DoubleQuotes))
+	assert.Equal(t, `// DO NOT EDIT. This file is automatically generated.
+
+export type Q = 'say "hi"' | 'it\'s' | 'back\\slash' | 'new\nline';
+`, render(typescript.SingleQuotes))
+}
+
+func TestSetRenderOptions_Render_FormattedAsPerOptions(t *testing.T) {
+	type Direction string
+
+	type Turtle struct {
+		Name      string
+		Direction Direction
+	}
+
+	go2ts := New()
+	go2ts.AddUnionToNamespace([]Direction{"up", "down"}, "compass")
+	go2ts.Add(Turtle{})
+	go2ts.EmitTypeGuards()
+	go2ts.SetRenderOptions(typescript.RenderOptions{
+		Indent:         "  ",
+		Quotes:         typescript.DoubleQuotes,
+		OmitSemicolons: true,
+		Header:         "// Copyright 2024 Example Inc.",
+		LineEnding:     "\r\n",
+	})
+
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	expected := `// Copyright 2024 Example Inc.
+
+export interface Turtle {
+  Name: string
+  Direction: compass.Direction
+}
+
+export namespace compass {
+  export type Direction = "up" | "down"
+
+  export function isDirection(x: unknown): x is compass.Direction {
+    return x === "up" || x === "down"
+  }
+}
+
+export function isTurtle(x: unknown): x is Turtle {
+  if (typeof x !== "object" || x === null || Array.isArray(x)) {
+    return false
+  }
+  const o = x as Record<string, unknown>
+  return (
+    typeof o["Name"] === "string" &&
+    compass.isDirection(o["Direction"])
+  )
+}
+`
+	assert.Equal(t, strings.ReplaceAll(expected, "\n", "\r\n"), b.String())
+}
+
+func TestCheck_UpToDate_ReturnsNil(t *testing.T) {
+	type Turtle struct {
+		Name string
+	}
+
+	go2ts := New()
+	go2ts.AddToNamespace(Turtle{}, "pond")
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+
+	diff, err := go2ts.Check(&b)
+	require.NoError(t, err)
+	assert.Nil(t, diff)
+}
+
+func TestCheck_Stale_ListsDeclarationsAndUnifiedDiff(t *testing.T) {
+	type Direction string
+
+	type Turtle struct {
+		Name      string
+		Direction Direction
+	}
+
+	type Frog struct {
+		Name string
+	}
+
+	existing := `// DO NOT EDIT. This file is automatically generated.
+
+export namespace pond {
+	export interface Turtle {
+		Name: string;
+	}
+
+	export interface Fish {
+		Fins: number;
+	}
+}
+`
+
+	go2ts := New()
+	go2ts.AddToNamespace(Turtle{}, "pond")
+	go2ts.AddToNamespace(Frog{}, "pond")
+	diff, err := go2ts.Check(strings.NewReader(existing))
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.Equal(t, []string{"pond.Frog", "pond.Direction"}, diff.Added)
+	assert.Equal(t, []string{"pond.Fish"}, diff.Removed)
+	assert.Equal(t, []string{"pond.Turtle"}, diff.Changed)
+	assert.Equal(t, `--- existing
++++ generated
+@@ -3,9 +3,12 @@
+ export namespace pond {
+ 	export interface Turtle {
+ 		Name: string;
++		Direction: pond.Direction;
+ 	}
+ 
+-	export interface Fish {
+-		Fins: number;
++	export interface Frog {
++		Name: string;
+ 	}
++
++	export type Direction = string;
+ }
+`, diff.Unified)
+	assert.True(t, strings.HasPrefix(diff.String(), "added: pond.Frog, pond.Direction\nremoved: pond.Fish\nchanged: pond.Turtle\n--- existing\n"))
+}
+
+func TestCompareOutput_HunksAndMissingNewline(t *testing.T) {
+	var existing, generated []string
+	for i := 1; i <= 20; i++ {
+		existing = append(existing, fmt.Sprintf("line %d", i))
+	}
+	generated = append(generated, existing...)
+	generated[1] = "line two"
+	generated[17] = "line eighteen"
+
+	diff := CompareOutput(strings.Join(existing, "\n")+"\n", strings.Join(generated, "\n"))
+	require.NotNil(t, diff)
+	assert.Empty(t, diff.Added)
+	assert.Empty(t, diff.Removed)
+	assert.Empty(t, diff.Changed)
+	assert.Equal(t, `--- existing
++++ generated
+@@ -1,5 +1,5 @@
+ line 1
+-line 2
++line two
+ line 3
+ line 4
+ line 5
+@@ -15,6 +15,6 @@
+ line 15
+ line 16
+ line 17
+-line 18
++line eighteen
+ line 19
+-line 20
++line 20
+\ No newline at end of file
+`, diff.Unified)
+
+	diff = CompareOutput("", "export type Name = string;\n")
+	require.NotNil(t, diff)
+	assert.Equal(t, []string{"Name"}, diff.Added)
+	assert.Equal(t, "--- existing\n+++ generated\n@@ -0,0 +1 @@\n+export type Name = string;\n", diff.Unified)
+}
+
+func TestDiffLines_RandomInputs_ShortestValidEditScript(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		a, b := make([]string, rnd.Intn(30)), make([]string, rnd.Intn(30))
+		for j := range a {
+			a[j] = string(rune('a' + rnd.Intn(4)))
+		}
+		for j := range b {
+			b[j] = string(rune('a' + rnd.Intn(4)))
+		}
+
+		// The length of the longest common subsequence, by dynamic programming.
+		lcs := make([][]int, len(a)+1)
+		for x := range lcs {
+			lcs[x] = make([]int, len(b)+1)
+		}
+		for x := len(a) - 1; x >= 0; x-- {
+			for y := len(b) - 1; y >= 0; y-- {
+				if a[x] == b[y] {
+					lcs[x][y] = lcs[x+1][y+1] + 1
+				} else if lcs[x+1][y] > lcs[x][y+1] {
+					lcs[x][y] = lcs[x+1][y]
+				} else {
+					lcs[x][y] = lcs[x][y+1]
+				}
+			}
+		}
+
+		from, to := []string{}, []string{}
+		changes := 0
+		for _, op := range diffLines(a, b) {
+			if op.kind != '+' {
+				from = append(from, op.line)
+			}
+			if op.kind != '-' {
+				to = append(to, op.line)
+			}
+			if op.kind != ' ' {
+				changes++
+			}
+		}
+		assert.Equal(t, a, from, "a=%q b=%q", a, b)
+		assert.Equal(t, b, to, "a=%q b=%q", a, b)
+		assert.Equal(t, len(a)+len(b)-2*lcs[0][0], changes, "a=%q b=%q", a, b)
+	}
+}
+
+func TestDiffLines_LargeInputsWithoutCommonLines_AllLinesReplaced(t *testing.T) {
+	// This would take gigabytes of memory if the diff were quadratic in space.
+	a, b := make([]string, 10000), make([]string, 10000)
+	for i := range a {
+		a[i], b[i] = fmt.Sprintf("a%d\n", i), fmt.Sprintf("b%d\n", i)
+	}
+
+	ops := diffLines(a, b)
+	require.Len(t, ops, 20000)
+	assert.Equal(t, diffOp{kind: '-', line: "a0\n"}, ops[0])
+	assert.Equal(t, diffOp{kind: '+', line: "b0\n"}, ops[10000])
+}
+
+func TestMarshalTypeDeclarations_Go2TSTypes_RoundTripRendersIdenticalTypeScript(t *testing.T) {
+	type SomeOption int
+
+	const (
+		OptionA SomeOption = 1
+		OptionB SomeOption = 3
+	)
+
+	type Leaf struct {
+		Options []SomeOption
+		Labels  map[string]string `json:",omitempty"`
+	}
+
+	type Tree struct {
+		// Children are the subtrees.
+		Children []*Tree
+		Leaves   map[string]Leaf
+	}
+
+	go2ts := New()
+	go2ts.SetSortOrder(SortByQualifiedName)
+	go2ts.EmitTypeGuards()
+	go2ts.AddToNamespace(Tree{}, "forest")
+	// The enum replaces the type alias referenced by Leaf.
+	go2ts.AddEnumWithNameToNamespace([2]SomeOption{OptionA, OptionB}, []string{"A", "B"}, "Option", "forest")
+
+	var expected bytes.Buffer
+	require.NoError(t, go2ts.Render(&expected))
+
+	data, err := typescript.MarshalTypeDeclarations(go2ts.TypeDeclarations())
+	require.NoError(t, err)
+	loaded, err := typescript.UnmarshalTypeDeclarations(data)
+	require.NoError(t, err)
+
+	var global typescript.Namespace
+	for _, typeDeclaration := range loaded {
+		global.AddTypeDeclaration(typeDeclaration)
+	}
+	for _, typeDeclaration := range loaded {
+		global.AddTypeGuard(typeDeclaration)
+	}
+	assert.Equal(t, expected.String(), typescript.RenderOptions{}.File(global.ToTypeScript()))
+}
+
+func TestEmitReadonly_PropertiesArraysAndMapsRenderedAsReadonly(t *testing.T) {
+	type Pond struct {
+		Depth int
+	}
+
+	type Lake struct {
+		Name   string
+		Ponds  []Pond
+		Fish   map[string][]int
+		Matrix [][]float64 `go2ts:"ignorenil"`
+	}
+
+	go2ts := New()
+	go2ts.EmitReadonly()
+	go2ts.Add(Lake{})
+
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	assert.Equal(t, `// DO NOT EDIT. This file is automatically generated.
+
+export interface Pond {
+	readonly Depth: number;
+}
+
+export interface Lake {
+	readonly Name: string;
+	readonly Ponds: readonly Pond[] | null;
+	readonly Fish: Readonly<Record<string, readonly number[] | null>> | null;
+	readonly Matrix: readonly (readonly number[])[];
+}
+`, b.String())
+}
+
+func TestReadonlyTag_OnlyTaggedFieldsRenderedAsReadonly(t *testing.T) {
+	type Pond struct {
+		Depth  int
+		Fishes []string
+	}
+
+	type Lake struct {
+		Name  string            `go2ts:"readonly"`
+		Tags  []string          `go2ts:"ignorenil,readonly"`
+		Ponds map[string][]Pond `go2ts:"readonly"`
+		Notes []string
+	}
+
+	go2ts := New()
+	go2ts.Add(Lake{})
+
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	assert.Equal(t, `// DO NOT EDIT. This file is automatically generated.
+
+export interface Pond {
+	Depth: number;
+	Fishes: string[] | null;
+}
+
+export interface Lake {
+	readonly Name: string;
+	readonly Tags: readonly string[];
+	readonly Ponds: Readonly<Record<string, readonly Pond[] | null>> | null;
+	Notes: string[] | null;
+}
+`, b.String())
+}
+
+func TestGo2TSTag_UnknownOption_Warns(t *testing.T) {
+	type Lake struct {
+		Name string `go2ts:"readonly,frozen"`
+	}
+
+	go2ts := New()
+	go2ts.Add(Lake{})
+	warnings := go2ts.Warnings()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, `Lake.Name: Unknown go2ts tag option "frozen"`, warnings[0].Error())
+}
+
+func TestSetMaxTupleLength_ShortArraysRenderedAsTuples(t *testing.T) {
+	type Point struct {
+		X, Y int
+	}
+
+	type Track struct {
+		Position [3]float64
+		Corners  [2]Point
+		Samples  [16]byte
+		Slice    []float64
+		Nested   [2][2]*int
+	}
+
+	go2ts := New()
+	go2ts.SetMaxTupleLength(3)
+	go2ts.Add(Track{})
+
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	assert.Equal(t, `// DO NOT EDIT. This file is automatically generated.
+
+export interface Point {
+	X: number;
+	Y: number;
+}
+
+export interface Track {
+	Position: [number, number, number];
+	Corners: [Point, Point];
+	Samples: number[];
+	Slice: number[] | null;
+	Nested: [[number | null, number | null], [number | null, number | null]];
+}
+`, b.String())
+}
+
+func TestSetMaxTupleLength_EmitReadonly_TuplesRenderedAsReadonly(t *testing.T) {
+	type Track struct {
+		Position [2]float64
+		Start    [2]float64 `go2ts:"readonly"`
+	}
+
+	go2ts := New()
+	go2ts.SetMaxTupleLength(2)
+	go2ts.Add(Track{})
+
+	var b bytes.Buffer
+	require.NoError(t, go2ts.Render(&b))
+	assert.Contains(t, b.String(), `
+export interface Track {
+	Position: [number, number];
+	readonly Start: readonly [number, number];
+}
+`)
+
+	go2ts = New()
+	go2ts.SetMaxTupleLength(2)
+	go2ts.EmitReadonly()
+	go2ts.Add(Track{})
+
+	b.Reset()
+	require.NoError(t, go2ts.Render(&b))
+	assert.Contains(t, b.String(), `
+export interface Track {
+	readonly Position: readonly [number, number];
+	readonly Start: readonly [number, number];
+}
+`)
+}
@@ -0,0 +1,284 @@
+package go2ts
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+)
+
+// goType abstracts over the two sources of Go type information supported by Go2TS:
+//   - reflect.Type, for values passed to the Add* methods.
+//   - types.Type, for types loaded from Go source code via the go/types package (e.g. by the go2ts
+//     command).
+//
+// Its methods mirror the subset of the reflect.Type API used by Go2TS, which allows both sources of
+// type information to share the same Go to TypeScript conversion code.
+type goType interface {
+	Kind() reflect.Kind
+	Name() string
+	PkgPath() string
+	String() string
+	Elem() goType
+	Key() goType
+	Len() int
+	NumField() int
+	Field(i int) goStructField
+
+	// implements returns true if the type implements the given interface, or if addressable is true
+	// and a pointer to the type implements the given interface. This is consistent with json.Marshal,
+	// which uses methods with pointer receivers on addressable values (e.g. struct fields), but not
+	// on non-addressable values (e.g. map keys).
+	implements(interfaceType reflect.Type, addressable bool) bool
+
+	// id returns a comparable value that uniquely identifies the type.
+	id() interface{}
+
+	// reflectType returns the underlying reflect.Type, or nil if the type was loaded from source code.
+	reflectType() reflect.Type
+}
+
+// goStructField mirrors the subset of the reflect.StructField API used by Go2TS.
+type goStructField struct {
+	Name      string
+	Type      goType
+	Tag       reflect.StructTag
+	Anonymous bool
+}
+
+// toGoType returns the goType of the given value, which can be an instance of a type, a
+// reflect.Type, a reflect.Value, or a types.Type.
+func toGoType(v interface{}) goType {
+	switch v := v.(type) {
+	case reflect.Type:
+		return reflectGoType{v}
+	case reflect.Value:
+		return reflectGoType{v.Type()}
+	case types.Type:
+		return newSourceGoType(v)
+	default:
+		return reflectGoType{reflect.TypeOf(v)}
+	}
+}
+
+///////////////////
+// reflectGoType //
+///////////////////
+
+// reflectGoType implements the goType interface for types obtained via reflection.
+type reflectGoType struct {
+	reflect.Type
+}
+
+// Elem implements the goType interface.
+func (r reflectGoType) Elem() goType { return reflectGoType{r.Type.Elem()} }
+
+// Key implements the goType interface.
+func (r reflectGoType) Key() goType { return reflectGoType{r.Type.Key()} }
+
+// Field implements the goType interface.
+func (r reflectGoType) Field(i int) goStructField {
+	field := r.Type.Field(i)
+	return goStructField{
+		Name:      field.Name,
+		Type:      reflectGoType{field.Type},
+		Tag:       field.Tag,
+		Anonymous: field.Anonymous,
+	}
+}
+
+// implements implements the goType interface.
+func (r reflectGoType) implements(interfaceType reflect.Type, addressable bool) bool {
+	if r.Type.Implements(interfaceType) {
+		return true
+	}
+	return addressable && r.Type.Kind() != reflect.Ptr && reflect.PtrTo(r.Type).Implements(interfaceType)
+}
+
+// id implements the goType interface.
+func (r reflectGoType) id() interface{} { return r.Type }
+
+// reflectType implements the goType interface.
+func (r reflectGoType) reflectType() reflect.Type { return r.Type }
+
+var _ goType = reflectGoType{}
+
+//////////////////
+// sourceGoType //
+//////////////////
+
+// sourceGoType implements the goType interface for types loaded from Go source code.
+type sourceGoType struct {
+	// t is never a *types.Alias; see newSourceGoType().
+	t types.Type
+}
+
+// sourceTypeID is the type of the IDs of sourceGoTypes. It prevents collisions with the IDs of
+// reflectGoTypes.
+type sourceTypeID string
+
+func newSourceGoType(t types.Type) goType {
+	// Type aliases (e.g. "type Foo = Bar") are indistinguishable from the aliased types at runtime, so
+	// we always work with the aliased types.
+	return sourceGoType{types.Unalias(t)}
+}
+
+// basicKinds maps go/types basic kinds to their corresponding reflect.Kinds.
+var basicKinds = map[types.BasicKind]reflect.Kind{
+	types.Bool:          reflect.Bool,
+	types.Int:           reflect.Int,
+	types.Int8:          reflect.Int8,
+	types.Int16:         reflect.Int16,
+	types.Int32:         reflect.Int32,
+	types.Int64:         reflect.Int64,
+	types.Uint:          reflect.Uint,
+	types.Uint8:         reflect.Uint8,
+	types.Uint16:        reflect.Uint16,
+	types.Uint32:        reflect.Uint32,
+	types.Uint64:        reflect.Uint64,
+	types.Uintptr:       reflect.Uintptr,
+	types.Float32:       reflect.Float32,
+	types.Float64:       reflect.Float64,
+	types.Complex64:     reflect.Complex64,
+	types.Complex128:    reflect.Complex128,
+	types.String:        reflect.String,
+	types.UnsafePointer: reflect.UnsafePointer,
+}
+
+// Kind implements the goType interface.
+func (s sourceGoType) Kind() reflect.Kind {
+	switch u := s.t.Underlying().(type) {
+	case *types.Basic:
+		return basicKinds[u.Kind()]
+	case *types.Pointer:
+		return reflect.Ptr
+	case *types.Slice:
+		return reflect.Slice
+	case *types.Array:
+		return reflect.Array
+	case *types.Map:
+		return reflect.Map
+	case *types.Struct:
+		return reflect.Struct
+	case *types.Interface:
+		return reflect.Interface
+	case *types.Chan:
+		return reflect.Chan
+	case *types.Signature:
+		return reflect.Func
+	}
+	return reflect.Invalid
+}
+
+// Name implements the goType interface.
+func (s sourceGoType) Name() string {
+	switch t := s.t.(type) {
+	case *types.Named:
+		if t.TypeArgs().Len() == 0 {
+			return t.Obj().Name()
+		}
+		// Consistent with reflect, e.g. the name of Page[Item] is "Page[example.com/pkg.Item]".
+		typeArgs := make([]string, 0, t.TypeArgs().Len())
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			typeArgs = append(typeArgs, types.TypeString(t.TypeArgs().At(i), (*types.Package).Path))
+		}
+		return t.Obj().Name() + "[" + strings.Join(typeArgs, ",") + "]"
+	case *types.Basic:
+		// Consistent with reflect, e.g. the name of "byte" is "uint8".
+		return s.Kind().String()
+	}
+	return ""
+}
+
+// PkgPath implements the goType interface.
+func (s sourceGoType) PkgPath() string {
+	if named, ok := s.t.(*types.Named); ok && named.Obj().Pkg() != nil {
+		return named.Obj().Pkg().Path()
+	}
+	return ""
+}
+
+// String implements the goType interface.
+func (s sourceGoType) String() string {
+	return types.TypeString(s.t, func(p *types.Package) string { return p.Name() })
+}
+
+// Elem implements the goType interface.
+func (s sourceGoType) Elem() goType {
+	switch u := s.t.Underlying().(type) {
+	case *types.Pointer:
+		return newSourceGoType(u.Elem())
+	case *types.Slice:
+		return newSourceGoType(u.Elem())
+	case *types.Array:
+		return newSourceGoType(u.Elem())
+	case *types.Map:
+		return newSourceGoType(u.Elem())
+	case *types.Chan:
+		return newSourceGoType(u.Elem())
+	}
+	panic("Elem of invalid type " + s.String())
+}
+
+// Key implements the goType interface.
+func (s sourceGoType) Key() goType {
+	return newSourceGoType(s.t.Underlying().(*types.Map).Key())
+}
+
+// Len implements the goType interface.
+func (s sourceGoType) Len() int {
+	return int(s.t.Underlying().(*types.Array).Len())
+}
+
+// NumField implements the goType interface.
+func (s sourceGoType) NumField() int {
+	return s.t.Underlying().(*types.Struct).NumFields()
+}
+
+// Field implements the goType interface.
+func (s sourceGoType) Field(i int) goStructField {
+	structType := s.t.Underlying().(*types.Struct)
+	field := structType.Field(i)
+	return goStructField{
+		Name:      field.Name(),
+		Type:      newSourceGoType(field.Type()),
+		Tag:       reflect.StructTag(structType.Tag(i)),
+		Anonymous: field.Embedded(),
+	}
+}
+
+// implements implements the goType interface.
+//
+// Only method names and the number of parameters and results are compared, which is sufficient for
+// the interfaces Go2TS cares about (e.g. json.Marshaler).
+func (s sourceGoType) implements(interfaceType reflect.Type, addressable bool) bool {
+	// The method set of *T includes the methods declared with both value and pointer receivers.
+	methodSet := types.NewMethodSet(s.t)
+	if addressable && s.Kind() != reflect.Ptr && s.Kind() != reflect.Interface {
+		methodSet = types.NewMethodSet(types.NewPointer(s.t))
+	}
+
+	for i := 0; i < interfaceType.NumMethod(); i++ {
+		method := interfaceType.Method(i)
+		// Unexported methods are never part of the interfaces Go2TS cares about, so we can pass a nil
+		// package to Lookup().
+		selection := methodSet.Lookup(nil, method.Name)
+		if selection == nil {
+			return false
+		}
+		signature := selection.Type().(*types.Signature)
+		if signature.Params().Len() != method.Type.NumIn() || signature.Results().Len() != method.Type.NumOut() {
+			return false
+		}
+	}
+	return true
+}
+
+// id implements the goType interface.
+func (s sourceGoType) id() interface{} {
+	return sourceTypeID(types.TypeString(s.t, nil))
+}
+
+// reflectType implements the goType interface.
+func (s sourceGoType) reflectType() reflect.Type { return nil }
+
+var _ goType = sourceGoType{}
@@ -0,0 +1,410 @@
+// Package jsonschema renders TypeScript type declarations as a JSON Schema document.
+//
+// Given the type declarations produced by Go2TS (see go2ts.Go2TS.TypeDeclarations()), this package
+// writes a JSON Schema (draft 2020-12) document with one definition under "$defs" per type
+// declaration, which allows sharing the same API contracts with non-TypeScript consumers.
+//
+// See https://json-schema.org/draft/2020-12/json-schema-core.html.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/skia-dev/go2ts/typescript"
+)
+
+// Draft is the URI of the JSON Schema dialect of the documents written by Render.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Render writes a JSON Schema document for the given TypeScript type declarations to the given
+// io.Writer.
+//
+// Each type declaration is defined under "$defs" by its qualified name, e.g. "MyNamespace.MyType",
+// and references to type declarations are represented as "$ref" keywords, e.g.
+// {"$ref": "#/$defs/MyNamespace.MyType"}.
+//
+// JSON Schema has no equivalent to generic types, thus generic type declarations are not defined.
+// Instead, each of their instantiations is defined by its TypeScript name, e.g. "Page<Item>".
+func Render(w io.Writer, typeDeclarations []typescript.TypeDeclaration) error {
+	defs := object{}
+	var genericTypeReferences []*typescript.GenericTypeReference
+	for _, typeDeclaration := range typeDeclarations {
+		if len(typescript.TypeParameters(typeDeclaration)) > 0 {
+			continue
+		}
+		defs = append(defs, member{typeDeclaration.QualifiedName(), typeDeclarationSchema(typeDeclaration)})
+		genericTypeReferences = append(genericTypeReferences, findGenericTypeReferences(typeDeclaration)...)
+	}
+
+	// Instantiations of generic types might reference further instantiations, e.g. Page<Item> might
+	// reference List<Item>.
+	instantiated := map[string]bool{}
+	for len(genericTypeReferences) > 0 {
+		genericTypeReference := genericTypeReferences[0]
+		genericTypeReferences = genericTypeReferences[1:]
+		name := genericTypeReference.ToTypeScript()
+		if instantiated[name] {
+			continue
+		}
+		instantiated[name] = true
+		typeDeclaration := instantiate(genericTypeReference)
+		defs = append(defs, member{name, typeDeclarationSchema(typeDeclaration)})
+		genericTypeReferences = append(genericTypeReferences, findGenericTypeReferences(typeDeclaration)...)
+	}
+	document := object{
+		{"$schema", Draft},
+		{"$defs", defs},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(document)
+}
+
+// Ref returns the value of the "$ref" keyword that references the definition of the given type
+// declaration.
+func Ref(typeDeclaration typescript.TypeDeclaration) string {
+	return ref(typeDeclaration.QualifiedName())
+}
+
+// ref returns the value of the "$ref" keyword that references the definition with the given name,
+// which is escaped as needed, e.g. "Page<Item>" is referenced as "#/$defs/Page%3CItem%3E".
+func ref(name string) string {
+	return (&url.URL{Fragment: "/$defs/" + name}).String()
+}
+
+// typeDeclarationSchema returns the JSON Schema for the given type declaration.
+func typeDeclarationSchema(typeDeclaration typescript.TypeDeclaration) object {
+	var schema object
+	var doc string
+	switch typeDeclaration := typeDeclaration.(type) {
+	case *typescript.InterfaceDeclaration:
+		doc = typeDeclaration.Doc
+		properties := object{}
+		required := []string{}
+		for _, prop := range typeDeclaration.Properties {
+			propSchema := typeSchema(prop.Type)
+			if prop.Doc != "" {
+				propSchema = append(propSchema, member{"description", prop.Doc})
+			}
+			properties = append(properties, member{prop.Identifier, propSchema})
+			if !prop.Optional {
+				required = append(required, prop.Identifier)
+			}
+		}
+		schema = object{
+			{"type", "object"},
+			{"properties", properties},
+			{"required", required},
+		}
+	case *typescript.TypeAliasDeclaration:
+		doc = typeDeclaration.Doc
+		schema = typeSchema(typeDeclaration.Type)
+	case *typescript.EnumDeclaration:
+		doc = typeDeclaration.Doc
+		var values []interface{}
+		for _, enumMember := range typeDeclaration.Members {
+			values = append(values, literalValue(enumMember.Value))
+		}
+		schema = object{{"enum", values}}
+	default:
+		panic(fmt.Sprintf("Unknown TypeScript type declaration: %T.", typeDeclaration))
+	}
+
+	if doc != "" {
+		schema = append(object{{"description", doc}}, schema...)
+	}
+	return schema
+}
+
+// typeSchema returns the JSON Schema for the given type.
+func typeSchema(t typescript.Type) object {
+	switch t := t.(type) {
+	case typescript.BasicType:
+		if t == typescript.Any {
+			// The empty schema accepts any value.
+			return object{}
+		}
+		return object{{"type", string(t)}}
+	case *typescript.LiteralType:
+		return object{{"const", literalValue(t)}}
+	case *typescript.ArrayType:
+		return object{
+			{"type", "array"},
+			{"items", typeSchema(t.ItemsType)},
+		}
+	case *typescript.TupleType:
+		prefixItems := []object{}
+		for _, elementType := range t.ElementTypes {
+			prefixItems = append(prefixItems, typeSchema(elementType))
+		}
+		return object{
+			{"type", "array"},
+			{"prefixItems", prefixItems},
+			{"items", false},
+			{"minItems", len(t.ElementTypes)},
+		}
+	case *typescript.MapType:
+		// JSON object keys are always strings, even if the TypeScript index type is a number.
+		schema := object{{"type", "object"}}
+		if t.IsMappedType() {
+			// The keys of mapped types are restricted to the members of a union or enum.
+			schema = append(schema, member{"propertyNames", typeSchema(t.IndexType)})
+		}
+		return append(schema, member{"additionalProperties", typeSchema(t.ValueType)})
+	case typescript.UnionType:
+		return unionSchema(t)
+	case *typescript.UnionType:
+		return unionSchema(*t)
+	case *typescript.TypeReference:
+		return object{{"$ref", Ref(t.TypeDeclaration())}}
+	case *typescript.GenericTypeReference:
+		return object{{"$ref", ref(t.ToTypeScript())}}
+	case *typescript.TypeParameter:
+		// Generic type declarations are only rendered once instantiated. See Render().
+		panic(fmt.Sprintf("Uninstantiated TypeScript type parameter: %s.", t.Identifier))
+	}
+	panic(fmt.Sprintf("Unknown TypeScript type: %T.", t))
+}
+
+// unionSchema returns the JSON Schema for the given union type.
+//
+// Unions of literal types are represented with the "enum" keyword, and unions of null and a type
+// with a single "type" keyword (e.g. nillable slices) are represented as e.g.
+// {"type": ["array", "null"]}. Any other unions are represented with the "anyOf" keyword.
+func unionSchema(u typescript.UnionType) object {
+	// Unions with "any" accept anything.
+	for _, t := range u.Types {
+		if t == typescript.Any {
+			return object{}
+		}
+	}
+
+	var nonNullTypes []typescript.Type
+	for _, t := range u.Types {
+		if t != typescript.Null {
+			nonNullTypes = append(nonNullTypes, t)
+		}
+	}
+	nullable := len(nonNullTypes) < len(u.Types)
+	if len(nonNullTypes) == 0 {
+		return typeSchema(typescript.Null)
+	}
+
+	// Unions of literal types, e.g. 'up' | 'down' | null.
+	if values, ok := literalValues(nonNullTypes); ok {
+		if nullable {
+			values = append(values, nil)
+		}
+		return object{{"enum", values}}
+	}
+
+	if len(nonNullTypes) == 1 {
+		schema := typeSchema(nonNullTypes[0])
+		if !nullable {
+			return schema
+		}
+		if len(schema) > 0 && schema[0].key == "type" {
+			if typ, ok := schema[0].value.(string); ok {
+				return append(object{{"type", []string{typ, "null"}}}, schema[1:]...)
+			}
+		}
+	}
+
+	var schemas []object
+	for _, t := range u.Types {
+		schemas = append(schemas, typeSchema(t))
+	}
+	return object{{"anyOf", schemas}}
+}
+
+// literalValues returns the JSON values of the given types and true, or nil and false if any of the
+// types is not a literal type.
+func literalValues(types []typescript.Type) ([]interface{}, bool) {
+	var values []interface{}
+	for _, t := range types {
+		literal, ok := t.(*typescript.LiteralType)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, literalValue(literal))
+	}
+	return values, true
+}
+
+// literalValue returns the JSON value of the given literal type.
+func literalValue(literal *typescript.LiteralType) interface{} {
+	switch literal.BasicType {
+	case typescript.Boolean:
+		return literal.Literal == "true"
+	case typescript.Number:
+		return json.Number(literal.Literal)
+	}
+	return literal.Literal
+}
+
+//////////////
+// Generics //
+//////////////
+
+// instantiate returns a copy of the generic type declaration referenced by the given generic type
+// reference, with its type parameters replaced by the reference's type arguments.
+func instantiate(genericTypeReference *typescript.GenericTypeReference) typescript.TypeDeclaration {
+	typeDeclaration := genericTypeReference.TypeReference.TypeDeclaration()
+	typeArguments := map[*typescript.TypeParameter]typescript.Type{}
+	for i, typeParameter := range typescript.TypeParameters(typeDeclaration) {
+		if i < len(genericTypeReference.TypeArguments) {
+			typeArguments[typeParameter] = genericTypeReference.TypeArguments[i]
+		}
+	}
+
+	switch typeDeclaration := typeDeclaration.(type) {
+	case *typescript.InterfaceDeclaration:
+		instance := *typeDeclaration
+		instance.TypeParameters = nil
+		instance.Properties = nil
+		for _, prop := range typeDeclaration.Properties {
+			prop.Type = substitute(prop.Type, typeArguments)
+			instance.Properties = append(instance.Properties, prop)
+		}
+		return &instance
+	case *typescript.TypeAliasDeclaration:
+		instance := *typeDeclaration
+		instance.TypeParameters = nil
+		instance.Type = substitute(typeDeclaration.Type, typeArguments)
+		return &instance
+	}
+	panic(fmt.Sprintf("Unknown generic TypeScript type declaration: %T.", typeDeclaration))
+}
+
+// substitute returns the given type with any of the given type parameters replaced by their
+// corresponding type arguments.
+func substitute(t typescript.Type, typeArguments map[*typescript.TypeParameter]typescript.Type) typescript.Type {
+	switch t := t.(type) {
+	case *typescript.TypeParameter:
+		if typeArgument, ok := typeArguments[t]; ok {
+			return typeArgument
+		}
+	case *typescript.ArrayType:
+		return &typescript.ArrayType{ItemsType: substitute(t.ItemsType, typeArguments)}
+	case *typescript.TupleType:
+		tuple := &typescript.TupleType{}
+		for _, t := range t.ElementTypes {
+			tuple.ElementTypes = append(tuple.ElementTypes, substitute(t, typeArguments))
+		}
+		return tuple
+	case *typescript.MapType:
+		return &typescript.MapType{IndexType: t.IndexType, ValueType: substitute(t.ValueType, typeArguments)}
+	case typescript.UnionType:
+		return substitute(&t, typeArguments)
+	case *typescript.UnionType:
+		u := &typescript.UnionType{}
+		for _, t := range t.Types {
+			u.Types = append(u.Types, substitute(t, typeArguments))
+		}
+		return u
+	case *typescript.GenericTypeReference:
+		g := &typescript.GenericTypeReference{TypeReference: t.TypeReference}
+		for _, t := range t.TypeArguments {
+			g.TypeArguments = append(g.TypeArguments, substitute(t, typeArguments))
+		}
+		return g
+	}
+	return t
+}
+
+// findGenericTypeReferences returns all the generic type references in the given type declaration.
+func findGenericTypeReferences(typeDeclaration typescript.TypeDeclaration) []*typescript.GenericTypeReference {
+	var genericTypeReferences []*typescript.GenericTypeReference
+	var find func(t typescript.Type)
+	find = func(t typescript.Type) {
+		switch t := t.(type) {
+		case *typescript.ArrayType:
+			find(t.ItemsType)
+		case *typescript.TupleType:
+			for _, t := range t.ElementTypes {
+				find(t)
+			}
+		case *typescript.MapType:
+			find(t.ValueType)
+		case typescript.UnionType:
+			find(&t)
+		case *typescript.UnionType:
+			for _, t := range t.Types {
+				find(t)
+			}
+		case *typescript.GenericTypeReference:
+			genericTypeReferences = append(genericTypeReferences, t)
+			for _, t := range t.TypeArguments {
+				find(t)
+			}
+		}
+	}
+
+	switch typeDeclaration := typeDeclaration.(type) {
+	case *typescript.InterfaceDeclaration:
+		for _, prop := range typeDeclaration.Properties {
+			find(prop.Type)
+		}
+	case *typescript.TypeAliasDeclaration:
+		find(typeDeclaration.Type)
+	}
+	return genericTypeReferences
+}
+
+////////////
+// object //
+////////////
+
+// member is a member of a JSON object.
+type member struct {
+	key   string
+	value interface{}
+}
+
+// object is a JSON object whose members are marshaled in order, unlike maps, whose keys are sorted
+// by encoding/json.
+type object []member
+
+// MarshalJSON implements the json.Marshaler interface.
+func (o object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("{")
+	for i, m := range o {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		key, err := marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteString(":")
+		b.Write(value)
+	}
+	b.WriteString("}")
+	return b.Bytes(), nil
+}
+
+var _ json.Marshaler = object{}
+
+// marshal is like json.Marshal, but it doesn't escape HTML characters, e.g. the angle brackets in
+// the names of instantiated generic types such as "Page<Item>".
+func marshal(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
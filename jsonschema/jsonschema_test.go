@@ -0,0 +1,308 @@
+package jsonschema
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/skia-dev/go2ts"
+	"github.com/skia-dev/go2ts/typescript"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_Go2TSTypes_Success(t *testing.T) {
+	type Direction string
+
+	type Position struct {
+		X int
+		Y int
+	}
+
+	type Turtle struct {
+		Position  Position `json:"Coordinates"`
+		Direction Direction
+		Tags      []string `json:",omitempty"`
+		Stats     map[string]float64
+		Parent    *Turtle
+		Extra     interface{}
+	}
+
+	g := go2ts.New()
+	g.AddToNamespace(Turtle{}, "Foo")
+	g.AddUnionToNamespace([]Direction{"up", "down"}, "Foo")
+	var b bytes.Buffer
+	require.NoError(t, Render(&b, g.TypeDeclarations()))
+	expected := `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$defs": {
+		"Foo.Position": {
+			"type": "object",
+			"properties": {
+				"X": {
+					"type": "number"
+				},
+				"Y": {
+					"type": "number"
+				}
+			},
+			"required": [
+				"X",
+				"Y"
+			]
+		},
+		"Foo.Direction": {
+			"enum": [
+				"up",
+				"down"
+			]
+		},
+		"Foo.Turtle": {
+			"type": "object",
+			"properties": {
+				"Coordinates": {
+					"$ref": "#/$defs/Foo.Position"
+				},
+				"Direction": {
+					"$ref": "#/$defs/Foo.Direction"
+				},
+				"Tags": {
+					"type": [
+						"array",
+						"null"
+					],
+					"items": {
+						"type": "string"
+					}
+				},
+				"Stats": {
+					"type": [
+						"object",
+						"null"
+					],
+					"additionalProperties": {
+						"type": "number"
+					}
+				},
+				"Parent": {
+					"anyOf": [
+						{
+							"$ref": "#/$defs/Foo.Turtle"
+						},
+						{
+							"type": "null"
+						}
+					]
+				},
+				"Extra": {}
+			},
+			"required": [
+				"Coordinates",
+				"Direction",
+				"Stats",
+				"Parent",
+				"Extra"
+			]
+		}
+	}
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestRender_EnumsLiteralsAndDocs_Success(t *testing.T) {
+	speed := &typescript.EnumDeclaration{
+		Identifier: "Speed",
+		Doc:        "Speed is how fast.",
+		Members: []typescript.EnumMember{
+			{Identifier: "Slow", Value: &typescript.LiteralType{BasicType: typescript.Number, Literal: "1.5"}},
+			{Identifier: "Fast", Value: &typescript.LiteralType{BasicType: typescript.Number, Literal: "2"}},
+		},
+	}
+	answer := &typescript.TypeAliasDeclaration{
+		Identifier: "Answer",
+		Type: &typescript.UnionType{
+			Types: []typescript.Type{
+				&typescript.LiteralType{BasicType: typescript.Boolean, Literal: "true"},
+				&typescript.LiteralType{BasicType: typescript.String, Literal: "maybe"},
+				typescript.Null,
+			},
+		},
+	}
+	direction := &typescript.TypeAliasDeclaration{
+		Identifier: "Direction",
+		Type: &typescript.UnionType{
+			Types: []typescript.Type{
+				&typescript.LiteralType{BasicType: typescript.String, Literal: "up"},
+				&typescript.LiteralType{BasicType: typescript.String, Literal: "down"},
+			},
+		},
+	}
+	empty := &typescript.InterfaceDeclaration{
+		Identifier: "Empty",
+		Properties: []typescript.PropertySignature{
+			{Identifier: "Yes", Type: &typescript.LiteralType{BasicType: typescript.Boolean, Literal: "true"}, Doc: "Yes is always true."},
+			{Identifier: "Steps", Type: &typescript.MapType{IndexType: direction.TypeReference(), ValueType: typescript.Number}},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, Render(&b, []typescript.TypeDeclaration{speed, answer, direction, empty}))
+	expected := `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$defs": {
+		"Speed": {
+			"description": "Speed is how fast.",
+			"enum": [
+				1.5,
+				2
+			]
+		},
+		"Answer": {
+			"enum": [
+				true,
+				"maybe",
+				null
+			]
+		},
+		"Direction": {
+			"enum": [
+				"up",
+				"down"
+			]
+		},
+		"Empty": {
+			"type": "object",
+			"properties": {
+				"Yes": {
+					"const": true,
+					"description": "Yes is always true."
+				},
+				"Steps": {
+					"type": "object",
+					"propertyNames": {
+						"$ref": "#/$defs/Direction"
+					},
+					"additionalProperties": {
+						"type": "number"
+					}
+				}
+			},
+			"required": [
+				"Yes",
+				"Steps"
+			]
+		}
+	}
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestRender_TupleTypes_PrefixItems(t *testing.T) {
+	position := &typescript.TypeAliasDeclaration{
+		Identifier: "Position",
+		Type:       &typescript.TupleType{ElementTypes: []typescript.Type{typescript.Number, typescript.String}},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, Render(&b, []typescript.TypeDeclaration{position}))
+	assert.Equal(t, `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$defs": {
+		"Position": {
+			"type": "array",
+			"prefixItems": [
+				{
+					"type": "number"
+				},
+				{
+					"type": "string"
+				}
+			],
+			"items": false,
+			"minItems": 2
+		}
+	}
+}
+`, b.String())
+}
+
+func TestRender_GenericTypes_InstantiationsDefined(t *testing.T) {
+	item := &typescript.InterfaceDeclaration{
+		Identifier: "Item",
+	}
+	itemType := &typescript.TypeParameter{Identifier: "T"}
+	list := &typescript.TypeAliasDeclaration{
+		Identifier:     "List",
+		TypeParameters: []*typescript.TypeParameter{itemType},
+		Type:           &typescript.ArrayType{ItemsType: itemType},
+	}
+	pageType := &typescript.TypeParameter{Identifier: "T"}
+	page := &typescript.InterfaceDeclaration{
+		Identifier:     "Page",
+		TypeParameters: []*typescript.TypeParameter{pageType},
+		Properties: []typescript.PropertySignature{
+			{Identifier: "Items", Type: &typescript.GenericTypeReference{TypeReference: list.TypeReference(), TypeArguments: []typescript.Type{pageType}}},
+		},
+	}
+	catalog := &typescript.InterfaceDeclaration{
+		Identifier: "Catalog",
+		Properties: []typescript.PropertySignature{
+			{Identifier: "Items", Type: &typescript.GenericTypeReference{TypeReference: page.TypeReference(), TypeArguments: []typescript.Type{item.TypeReference()}}},
+			{Identifier: "Names", Type: &typescript.GenericTypeReference{TypeReference: list.TypeReference(), TypeArguments: []typescript.Type{typescript.String}}},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, Render(&b, []typescript.TypeDeclaration{item, list, page, catalog}))
+	expected := `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"$defs": {
+		"Item": {
+			"type": "object",
+			"properties": {},
+			"required": []
+		},
+		"Catalog": {
+			"type": "object",
+			"properties": {
+				"Items": {
+					"$ref": "#/$defs/Page%3CItem%3E"
+				},
+				"Names": {
+					"$ref": "#/$defs/List%3Cstring%3E"
+				}
+			},
+			"required": [
+				"Items",
+				"Names"
+			]
+		},
+		"Page<Item>": {
+			"type": "object",
+			"properties": {
+				"Items": {
+					"$ref": "#/$defs/List%3CItem%3E"
+				}
+			},
+			"required": [
+				"Items"
+			]
+		},
+		"List<string>": {
+			"type": "array",
+			"items": {
+				"type": "string"
+			}
+		},
+		"List<Item>": {
+			"type": "array",
+			"items": {
+				"$ref": "#/$defs/Item"
+			}
+		}
+	}
+}
+`
+	assert.Equal(t, expected, b.String())
+}
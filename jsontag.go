@@ -0,0 +1,115 @@
+package go2ts
+
+import (
+	"strings"
+	"unicode"
+)
+
+// jsonTag is a parsed `json:...` struct field tag.
+//
+// See https://pkg.go.dev/encoding/json#Marshal for the meaning of each option.
+type jsonTag struct {
+	// ignored is true if the tag is exactly "-", which means the field is not serialized. Note that
+	// `json:"-,"` names the JSON property "-" instead.
+	ignored bool
+
+	// name is the name of the JSON property, or the empty string to use the field name.
+	name string
+
+	// invalidName holds the name in the tag if encoding/json ignores it for being invalid, in which
+	// case name is the empty string. See isValidJSONTagName().
+	invalidName string
+
+	// omitEmpty is true if the tag includes the "omitempty" option.
+	omitEmpty bool
+
+	// omitZero is true if the tag includes the "omitzero" option (Go 1.24+).
+	omitZero bool
+
+	// quoted is true if the tag includes the "string" option.
+	quoted bool
+
+	// unknownOptions holds any options not recognized by encoding/json, in order.
+	unknownOptions []string
+}
+
+// parseJSONTag parses a struct field's `json:...` tag. Options are recognized regardless of their
+// position, e.g. `json:",omitempty,string"` and `json:",string,omitempty"` are equivalent. Empty
+// options (e.g. `json:"name,"`) are ignored.
+func parseJSONTag(tag string) jsonTag {
+	if tag == "-" {
+		return jsonTag{ignored: true}
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	parsed := jsonTag{name: name}
+	if name != "" && !isValidJSONTagName(name) {
+		parsed = jsonTag{invalidName: name}
+	}
+	for options != "" {
+		var option string
+		option, options, _ = strings.Cut(options, ",")
+		switch option {
+		case "":
+		case "omitempty":
+			parsed.omitEmpty = true
+		case "omitzero":
+			parsed.omitZero = true
+		case "string":
+			parsed.quoted = true
+		default:
+			parsed.unknownOptions = append(parsed.unknownOptions, option)
+		}
+	}
+	return parsed
+}
+
+// isValidJSONTagName returns true if encoding/json accepts the given name in a `json:...` tag,
+// i.e. if it only contains letters, digits and punctuation other than backslashes and quotes.
+// Consistent with json.Marshal, invalid names are ignored in favor of the field name.
+func isValidJSONTagName(name string) bool {
+	for _, c := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
+
+// optional returns true if the JSON property might be omitted when marshaling.
+func (t jsonTag) optional() bool {
+	return t.omitEmpty || t.omitZero
+}
+
+// go2tsTag is a parsed `go2ts:...` struct field tag, e.g. `go2ts:"ignorenil,readonly"`.
+type go2tsTag struct {
+	// ignoreNil is true if the tag includes the "ignorenil" option.
+	ignoreNil bool
+
+	// readonly is true if the tag includes the "readonly" option.
+	readonly bool
+
+	// unknownOptions holds any unrecognized options, in order.
+	unknownOptions []string
+}
+
+// parseGo2TSTag parses a struct field's `go2ts:...` tag, which is a comma-separated list of options.
+// Empty options are ignored.
+func parseGo2TSTag(tag string) go2tsTag {
+	var parsed go2tsTag
+	for tag != "" {
+		var option string
+		option, tag, _ = strings.Cut(tag, ",")
+		switch option {
+		case "":
+		case "ignorenil":
+			parsed.ignoreNil = true
+		case "readonly":
+			parsed.readonly = true
+		default:
+			parsed.unknownOptions = append(parsed.unknownOptions, option)
+		}
+	}
+	return parsed
+}
@@ -0,0 +1,47 @@
+// Package loader loads Go packages from source code via the golang.org/x/tools/go/packages package,
+// for Go2TS to inspect the source code of Go types obtained via reflection, e.g.:
+//
+//	generator := go2ts.New()
+//	generator.SetPackageLoader(loader.Load)
+//	generator.DiscoverConstUnions()
+//
+// It is a separate package such that users of the go2ts package who never load packages from
+// source code don't depend on golang.org/x/tools.
+package loader
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Mode is the packages.LoadMode needed by Go2TS to inspect the source code of a package, i.e. its
+// type information and syntax trees.
+//
+// We type-check dependencies from source (packages.NeedDeps) rather than loading their export data,
+// which is less efficient but doesn't depend on the export data format of the installed Go
+// toolchain.
+const Mode = packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax
+
+// Load loads the Go package with the given import path from source code, and returns its type
+// information and the syntax trees of its files. It implements go2ts.PackageLoader.
+func Load(pkgPath string) (*types.Package, []*ast.File, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: Mode}, pkgPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, nil, fmt.Errorf("found %d packages", len(pkgs))
+	}
+	if len(pkgs[0].Errors) > 0 {
+		var errs []string
+		for _, pkgErr := range pkgs[0].Errors {
+			errs = append(errs, pkgErr.Error())
+		}
+		return nil, nil, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return pkgs[0].Types, pkgs[0].Syntax, nil
+}
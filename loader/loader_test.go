@@ -0,0 +1,22 @@
+package loader
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_StandardLibraryPackage_Success(t *testing.T) {
+	pkg, syntax, err := Load("time")
+	require.NoError(t, err)
+	assert.Equal(t, "time", pkg.Path())
+	assert.IsType(t, &types.TypeName{}, pkg.Scope().Lookup("Weekday"))
+	assert.NotEmpty(t, syntax)
+}
+
+func TestLoad_NonexistentPackage_Error(t *testing.T) {
+	_, _, err := Load("example.com/nonexistent")
+	require.Error(t, err)
+}
@@ -0,0 +1,150 @@
+package go2ts
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/skia-dev/go2ts/typescript"
+)
+
+// ModuleGrouping determines how Go2TS groups TypeScript type declarations into ES modules, i.e.
+// .ts files. See Modules().
+type ModuleGrouping int
+
+const (
+	// GroupByPackage declares the TypeScript types for the Go types of each Go package in a separate
+	// module. Modules are named after the import paths of their packages, relative to the longest
+	// common parent directory of all packages, e.g. "api/v1" and "api/v2" for
+	// "github.com/example/api/v1" and "github.com/example/api/v2". TypeScript types for Go types
+	// without a package (i.e. anonymous structs) are declared in the "anonymous" module.
+	GroupByPackage ModuleGrouping = iota
+
+	// GroupByNamespace declares the TypeScript types in each namespace in a separate module. Modules
+	// are named after their namespaces, e.g. "api/v1" for "api.v1". TypeScript types in the global
+	// namespace are declared in the "index" module.
+	GroupByNamespace
+)
+
+// Modules returns ES modules that declare the TypeScript types for all the Go types added so far,
+// grouped as per the given grouping, in the order they should be written (see typescript.Modules()).
+// References to TypeScript types in other modules are imported, e.g.
+// "import type { Job } from './api/v1';", and namespaces are ignored within modules.
+//
+// Modules include type guard functions if EmitTypeGuards() was called.
+//
+// It returns an error if any TypeScript types would be declared more than once in the same module,
+// e.g. "foo.Config" and "bar.Config" with GroupByPackage, or any errors accumulated in
+// accumulated-errors mode (see AccumulateErrors()).
+func (g *Go2TS) Modules(grouping ModuleGrouping) ([]*typescript.Module, error) {
+	if err := g.Err(); err != nil {
+		return nil, err
+	}
+
+	typeDeclarations := g.typeDeclarationsInRenderOrder()
+	packagePaths := packageModulePaths(g.packagePaths)
+	modules := typescript.Modules(typeDeclarations, func(typeDeclaration typescript.TypeDeclaration) string {
+		if grouping == GroupByNamespace {
+			return namespaceModulePath(typeDeclaration)
+		}
+		return packagePaths[g.packagePaths[typeDeclaration]]
+	})
+
+	for _, module := range modules {
+		module.TypeGuards = g.emitTypeGuards
+
+		declared := map[string]typescript.TypeDeclaration{}
+		for _, typeDeclaration := range module.TypeDeclarations {
+			identifier := typeDeclaration.QualifiedName()
+			if i := strings.LastIndex(identifier, "."); i >= 0 {
+				identifier = identifier[i+1:]
+			}
+			if existing, ok := declared[identifier]; ok {
+				return nil, fmt.Errorf("TypeScript types %q and %q cannot both be declared as %q in module %q", existing.QualifiedName(), typeDeclaration.QualifiedName(), identifier, module.Path)
+			}
+			declared[identifier] = typeDeclaration
+		}
+	}
+	return modules, nil
+}
+
+// RenderModules writes the ES modules returned by Modules() to the given directory, one .ts file per
+// module, e.g. "api/v1.ts", creating any directories as needed. The TypeScript code is formatted
+// as per the render options (see SetRenderOptions()).
+//
+// In accumulated-errors mode, RenderModules writes nothing and returns the accumulated errors if
+// there are any. See AccumulateErrors().
+func (g *Go2TS) RenderModules(dir string, grouping ModuleGrouping) error {
+	modules, err := g.Modules(grouping)
+	if err != nil {
+		return err
+	}
+
+	for _, module := range modules {
+		filename := filepath.Join(dir, filepath.FromSlash(module.Path)+".ts")
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		contents := g.renderOptions.File(module.ToTypeScriptWithOptions(g.renderOptions))
+		if err := os.WriteFile(filename, []byte(contents), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// anonymousModulePath is the path of the module for Go types without a package with
+// GroupByPackage.
+const anonymousModulePath = "anonymous"
+
+// globalNamespaceModulePath is the path of the module for the global namespace with
+// GroupByNamespace.
+const globalNamespaceModulePath = "index"
+
+// packageModulePaths maps the import paths of the packages of the given type declarations to their
+// module paths, which are relative to the longest common parent directory of all the packages. See
+// GroupByPackage.
+func packageModulePaths(packagePaths map[typescript.TypeDeclaration]string) map[string]string {
+	// Find the longest common parent directory, e.g. ["github.com", "example"] for
+	// "github.com/example/foo" and "github.com/example/bar/baz".
+	var parent []string
+	first := true
+	for _, pkgPath := range packagePaths {
+		if pkgPath == "" {
+			continue
+		}
+		dirs := strings.Split(pkgPath, "/")
+		dirs = dirs[:len(dirs)-1]
+		if first {
+			parent = dirs
+			first = false
+			continue
+		}
+		common := 0
+		for common < len(parent) && common < len(dirs) && parent[common] == dirs[common] {
+			common++
+		}
+		parent = parent[:common]
+	}
+
+	modulePaths := map[string]string{"": anonymousModulePath}
+	for _, pkgPath := range packagePaths {
+		if pkgPath != "" {
+			modulePaths[pkgPath] = strings.Join(strings.Split(pkgPath, "/")[len(parent):], "/")
+		}
+	}
+	return modulePaths
+}
+
+// namespaceModulePath returns the path of the module for the namespace of the given type
+// declaration. See GroupByNamespace.
+func namespaceModulePath(typeDeclaration typescript.TypeDeclaration) string {
+	qualifiedName := typeDeclaration.QualifiedName()
+	i := strings.LastIndex(qualifiedName, ".")
+	if i < 0 {
+		return globalNamespaceModulePath
+	}
+	return path.Join(strings.Split(qualifiedName[:i], ".")...)
+}
@@ -0,0 +1,67 @@
+package go2ts
+
+import (
+	"sort"
+
+	"github.com/skia-dev/go2ts/typescript"
+)
+
+// SortOrder determines the order in which Go2TS outputs TypeScript type declarations. See
+// SetSortOrder().
+type SortOrder int
+
+const (
+	// AddedOrder outputs TypeScript type declarations in the order their Go types were added or
+	// discovered (e.g. while traversing the fields of a struct), with any type declarations a given
+	// type declaration depends on before it. Render() outputs TypeScript interfaces first, and any
+	// other type declarations second.
+	//
+	// This is the default order.
+	AddedOrder SortOrder = iota
+
+	// SortByQualifiedName outputs TypeScript type declarations sorted by qualified name, e.g.
+	// "Turtle" before "api.Job".
+	SortByQualifiedName
+
+	// SortByPackage outputs TypeScript type declarations sorted by the import paths of the packages
+	// of their Go types, and then by qualified name. Type declarations for Go types without a package
+	// (i.e. anonymous structs) come first.
+	SortByPackage
+)
+
+// SetSortOrder sets the order in which Render(), Modules() and TypeDeclarations() output TypeScript
+// type declarations. Defaults to AddedOrder.
+//
+// With SortByQualifiedName or SortByPackage, the output doesn't depend on the order in which Go types
+// are added, e.g. reordering calls to Add() neither reorders the output nor renames types, be it
+// anonymous structs (e.g. "Anonymous1") or types renamed to resolve name collisions, provided that
+// the name collision strategy is PrefixPackageName (see SetNameCollisionStrategy()).
+//
+// TypeScript type declarations can reference each other regardless of their order, so the output
+// remains valid. Alternate renderers must handle references to type declarations that appear later,
+// as the zod package does.
+//
+// Regardless of the sort order, the same Go types added in the same order always produce
+// byte-identical output.
+func (g *Go2TS) SetSortOrder(order SortOrder) {
+	g.sortOrder = order
+}
+
+// sortTypeDeclarations sorts the given type declarations in place as per the sort order, unless it
+// is AddedOrder. See SetSortOrder().
+func (g *Go2TS) sortTypeDeclarations(typeDeclarations []typescript.TypeDeclaration) {
+	switch g.sortOrder {
+	case SortByQualifiedName:
+		sort.SliceStable(typeDeclarations, func(i, j int) bool {
+			return typeDeclarations[i].QualifiedName() < typeDeclarations[j].QualifiedName()
+		})
+	case SortByPackage:
+		sort.SliceStable(typeDeclarations, func(i, j int) bool {
+			pkgPathI, pkgPathJ := g.packagePaths[typeDeclarations[i]], g.packagePaths[typeDeclarations[j]]
+			if pkgPathI != pkgPathJ {
+				return pkgPathI < pkgPathJ
+			}
+			return typeDeclarations[i].QualifiedName() < typeDeclarations[j].QualifiedName()
+		})
+	}
+}
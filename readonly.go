@@ -0,0 +1,48 @@
+package go2ts
+
+import "github.com/skia-dev/go2ts/typescript"
+
+// EmitReadonly makes Go2TS declare all TypeScript interface properties, arrays and maps as
+// readonly, e.g. "readonly Tags: readonly string[] | null;", which suits frontends that treat state
+// as immutable.
+//
+// Alternatively, individual struct fields can be declared as readonly via the `go2ts:"readonly"`
+// tag, which also makes any arrays and maps in the type of the field readonly, except for those in
+// other type declarations. See typescript.PropertySignature, typescript.ArrayType and
+// typescript.MapType.
+func (g *Go2TS) EmitReadonly() {
+	g.emitReadonly = true
+}
+
+// readonlyType returns a copy of the given type with any arrays, tuples and maps made readonly,
+// including nested ones. Referenced type declarations are left as is.
+func readonlyType(t typescript.Type) typescript.Type {
+	switch t := t.(type) {
+	case *typescript.ArrayType:
+		return &typescript.ArrayType{
+			ItemsType: readonlyType(t.ItemsType),
+			Readonly:  true,
+		}
+	case *typescript.TupleType:
+		tupleType := &typescript.TupleType{Readonly: true}
+		for _, t := range t.ElementTypes {
+			tupleType.ElementTypes = append(tupleType.ElementTypes, readonlyType(t))
+		}
+		return tupleType
+	case *typescript.MapType:
+		return &typescript.MapType{
+			IndexType: t.IndexType,
+			ValueType: readonlyType(t.ValueType),
+			Readonly:  true,
+		}
+	case typescript.UnionType:
+		return *readonlyType(&t).(*typescript.UnionType)
+	case *typescript.UnionType:
+		unionType := &typescript.UnionType{}
+		for _, t := range t.Types {
+			unionType.Types = append(unionType.Types, readonlyType(t))
+		}
+		return unionType
+	}
+	return t
+}
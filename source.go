@@ -0,0 +1,229 @@
+package go2ts
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/skia-dev/go2ts/typescript"
+)
+
+// AddConstUnion adds a TypeScript definition for a union type of the values of all the constants of
+// the given Go type.
+//
+// See AddConstUnionWithNameToNamespace() for more details.
+func (g *Go2TS) AddConstUnion(v interface{}) {
+	g.AddConstUnionWithNameToNamespace(v, "", "")
+}
+
+// AddConstUnionToNamespace adds a TypeScript definition for a union type of the values of all the
+// constants of the given Go type to the given TypeScript namespace.
+//
+// See AddConstUnionWithNameToNamespace() for more details.
+func (g *Go2TS) AddConstUnionToNamespace(v interface{}, namespace string) {
+	g.AddConstUnionWithNameToNamespace(v, "", namespace)
+}
+
+// AddConstUnionWithName adds a TypeScript definition for a union type of the values of all the
+// constants of the given Go type.
+//
+// See AddConstUnionWithNameToNamespace() for more details.
+func (g *Go2TS) AddConstUnionWithName(v interface{}, typeName string) {
+	g.AddConstUnionWithNameToNamespace(v, typeName, "")
+}
+
+// AddConstUnionWithNameToNamespace adds a TypeScript definition for a union type of the values of
+// all the constants of the given Go type to the given namespace.
+//
+// The value passed in can be an instance of a type, a reflect.Type, a reflect.Value, or a
+// types.Type, and must be a named type whose underlying type is a boolean, a number or a string,
+// e.g. "type Direction string". The Go package that declares the type is statically analyzed to
+// find all the package-level constants of said type, including iota-based constants, which are
+// added to the union type in the order they are declared.
+//
+// This is an alternative to AddUnionWithNameToNamespace() that doesn't require maintaining a slice
+// with all the values of an enum-like type. The package of a Go type obtained via reflection must be
+// provided via UsePackage() or loaded from source code via SetPackageLoader(), thus the type must be
+// declared at the package level, and the package source code must be available.
+//
+// If typeName is the empty string then the name of the Go type is used as the type name.
+func (g *Go2TS) AddConstUnionWithNameToNamespace(v interface{}, typeName, namespace string) {
+	typ := toGoType(v)
+	path := rootPath(typ)
+
+	unionType, err := g.constUnionType(typ)
+	if err != nil {
+		g.fail(typ, path, err.Error())
+		return
+	}
+
+	if typeName == "" {
+		typeName = typ.Name()
+	}
+	g.addUnionTypeDeclaration(typ, typeName, namespace, path, unionType)
+}
+
+// DiscoverConstUnions makes Go2TS automatically declare named Go types whose underlying type is a
+// boolean, a number or a string as TypeScript union types, if the package that declares them also
+// declares constants of said types. See AddConstUnionWithNameToNamespace() for details.
+//
+// Named Go types for which no constants can be found are declared as usual, e.g. "type Mode string"
+// is declared as "export type Mode = string".
+//
+// Note that this will load the packages of any such Go types obtained via reflection from source
+// code via the package loader (see SetPackageLoader()), which can be slow.
+func (g *Go2TS) DiscoverConstUnions() {
+	g.discoverConstUnions = true
+}
+
+// constUnionType returns a union type of the values of the constants of the given Go type.
+func (g *Go2TS) constUnionType(typ goType) (*typescript.UnionType, error) {
+	if !isPrimitiveAlias(typ) {
+		return nil, fmt.Errorf("Go type %v cannot be used in a TypeScript union type; it must be a named boolean, number or string type", typ)
+	}
+	// Addressable values include those whose marshaler methods have pointer receivers.
+	if g.isCustomType(typ, true) {
+		return nil, fmt.Errorf("Go type %v has a custom type mapping, so its constants do not match its JSON representation", typ)
+	}
+
+	named, err := g.lookupNamedType(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find all the constants of the named type, in the order they were declared.
+	var constants []*types.Const
+	scope := named.Obj().Pkg().Scope()
+	for _, name := range scope.Names() {
+		if obj, ok := scope.Lookup(name).(*types.Const); ok && types.Identical(obj.Type(), named) {
+			constants = append(constants, obj)
+		}
+	}
+	sort.SliceStable(constants, func(i, j int) bool {
+		return constants[i].Pos() < constants[j].Pos()
+	})
+	if len(constants) == 0 {
+		return nil, fmt.Errorf("No constants of Go type %v found in package %q", typ, named.Obj().Pkg().Path())
+	}
+
+	basicType := typescript.String
+	if typ.Kind() == reflect.Bool {
+		basicType = typescript.Boolean
+	} else if isNumber(typ.Kind()) {
+		basicType = typescript.Number
+	}
+
+	unionType := &typescript.UnionType{
+		Types: []typescript.Type{},
+	}
+	seen := map[string]bool{}
+	for _, obj := range constants {
+		// Skip duplicate values, e.g. "const Default = Up".
+		literal := constantLiteral(obj.Val())
+		if seen[literal] {
+			continue
+		}
+		seen[literal] = true
+
+		unionType.Types = append(unionType.Types, &typescript.LiteralType{
+			BasicType: basicType,
+			Literal:   literal,
+		})
+	}
+	return unionType, nil
+}
+
+// constantLiteral returns the literal that should be used in a typescript.LiteralType to represent
+// the given constant value.
+func constantLiteral(value constant.Value) string {
+	switch value.Kind() {
+	case constant.String:
+		return constant.StringVal(value)
+	case constant.Float:
+		f, _ := constant.Float64Val(value)
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	default:
+		return value.ExactString()
+	}
+}
+
+// lookupNamedType returns the *types.Named corresponding to the given Go type. Go types obtained via
+// reflection are looked up in their package, which is loaded from source code.
+func (g *Go2TS) lookupNamedType(typ goType) (*types.Named, error) {
+	if sourceType, ok := typ.(sourceGoType); ok {
+		if named, ok := sourceType.t.(*types.Named); ok {
+			return named, nil
+		}
+		return nil, fmt.Errorf("Go type %v is not a named type", typ)
+	}
+
+	if typ.Name() == "" || typ.PkgPath() == "" {
+		return nil, fmt.Errorf("Go type %v is not a named type declared in a package", typ)
+	}
+	pkg, err := g.loadPackage(typ.PkgPath())
+	if err != nil {
+		return nil, err
+	}
+	typeName, ok := pkg.types.Scope().Lookup(typ.Name()).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("Go type %v is not declared at the package level of package %q", typ, typ.PkgPath())
+	}
+	named, ok := typeName.Type().(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("Go type %v is not a named type", typ)
+	}
+	return named, nil
+}
+
+// PackageLoader loads the Go package with the given import path from source code, and returns its
+// type information and the syntax trees of its files, including their comments.
+//
+// The golang.org/x/tools/go/packages package can be used to implement a PackageLoader, see the
+// github.com/skia-dev/go2ts/loader package.
+type PackageLoader func(pkgPath string) (*types.Package, []*ast.File, error)
+
+// SetPackageLoader sets the function used to load the packages of Go types obtained via reflection
+// from source code, whenever Go2TS needs to inspect their source code (see DiscoverConstUnions() and
+// EmitDocComments()) and they weren't provided via UsePackage().
+//
+// By default there is no package loader, and such packages fail to load.
+func (g *Go2TS) SetPackageLoader(loader PackageLoader) {
+	g.packageLoader = loader
+}
+
+// sourcePackage is a Go package loaded from source code.
+type sourcePackage struct {
+	// types holds the type information of the package.
+	types *types.Package
+
+	// syntax holds the syntax trees of the package's files.
+	syntax []*ast.File
+}
+
+// loadPackage loads the Go package with the given import path from source code via the package
+// loader. Packages are loaded at most once.
+func (g *Go2TS) loadPackage(pkgPath string) (*sourcePackage, error) {
+	if pkg, ok := g.packages[pkgPath]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("Failed to load package %q", pkgPath)
+		}
+		return pkg, nil
+	}
+
+	if g.packageLoader == nil {
+		return nil, fmt.Errorf("Failed to load package %q: no package loader, see SetPackageLoader()", pkgPath)
+	}
+	pkgTypes, syntax, err := g.packageLoader(pkgPath)
+	if err != nil {
+		g.packages[pkgPath] = nil
+		return nil, fmt.Errorf("Failed to load package %q: %s", pkgPath, err)
+	}
+
+	pkg := &sourcePackage{types: pkgTypes, syntax: syntax}
+	g.packages[pkgPath] = pkg
+	return pkg, nil
+}
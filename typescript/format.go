@@ -0,0 +1,154 @@
+package typescript
+
+import (
+	"strings"
+	"unicode"
+)
+
+// QuoteStyle determines the quotes around string literals in TypeScript code.
+type QuoteStyle int
+
+const (
+	// SingleQuotes renders string literals with single quotes, e.g. 'up'.
+	SingleQuotes QuoteStyle = iota
+
+	// DoubleQuotes renders string literals with double quotes, e.g. "up".
+	DoubleQuotes
+)
+
+// DefaultHeader is the header of the files rendered with the default RenderOptions.
+const DefaultHeader = "// DO NOT EDIT. This file is automatically generated."
+
+// RenderOptions determines how TypeScript code is formatted, e.g. to match the configuration of a
+// code formatter such as prettier. The zero value formats TypeScript code with tabs, single quotes
+// and semicolons, which is what the ToTypeScript() methods do.
+type RenderOptions struct {
+	// Indent is the indentation of each nesting level, e.g. "  ". Defaults to a tab.
+	Indent string
+
+	// Quotes determines the quotes around string literals. Defaults to SingleQuotes.
+	Quotes QuoteStyle
+
+	// OmitSemicolons omits the semicolons at the end of statements and property signatures.
+	OmitSemicolons bool
+
+	// Header is the comment at the top of each rendered file (see File()), e.g. a license banner.
+	// Defaults to DefaultHeader.
+	Header string
+
+	// LineEnding is the line ending of each rendered file (see File()), e.g. "\r\n". Defaults to
+	// "\n". TypeScript code returned by the ToTypeScript*() methods always uses "\n".
+	LineEnding string
+}
+
+// File returns the contents of a TypeScript file with the given code, i.e. the header followed by a
+// blank line and the code, with a line ending at the end of every line.
+func (o RenderOptions) File(code string) string {
+	header := o.Header
+	if header == "" {
+		header = DefaultHeader
+	}
+	contents := strings.TrimRight(header, "\n") + "\n"
+	if code != "" {
+		contents += "\n" + code + "\n"
+	}
+	if o.LineEnding != "" && o.LineEnding != "\n" {
+		contents = strings.ReplaceAll(contents, "\n", o.LineEnding)
+	}
+	return contents
+}
+
+// Indentation returns the indentation of each nesting level, i.e. Indent or its default.
+func (o RenderOptions) Indentation() string {
+	if o.Indent == "" {
+		return "\t"
+	}
+	return o.Indent
+}
+
+// Quote returns the given string as a string literal enclosed in quotes as per the quote style,
+// e.g. 'zod'. Backslashes, line breaks and the quotes themselves are escaped, e.g. 'it\'s'.
+func (o RenderOptions) Quote(s string) string {
+	if o.Quotes == DoubleQuotes {
+		return `"` + doubleQuotesEscaper.Replace(s) + `"`
+	}
+	return "'" + singleQuotesEscaper.Replace(s) + "'"
+}
+
+var (
+	// singleQuotesEscaper escapes strings enclosed in single quotes. See Quote().
+	singleQuotesEscaper = strings.NewReplacer(`\`, `\\`, "'", `\'`, "\n", `\n`, "\r", `\r`, "\u2028", `\u2028`, "\u2029", `\u2029`)
+
+	// doubleQuotesEscaper escapes strings enclosed in double quotes. See Quote().
+	doubleQuotesEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\u2028", `\u2028`, "\u2029", `\u2029`)
+)
+
+// PropertyName returns the given property name as is if it is a valid TypeScript identifier, e.g.
+// Name, or as a string literal otherwise, e.g. 'first-name' or '-'. See Quote().
+func (o RenderOptions) PropertyName(name string) string {
+	if IsIdentifier(name) {
+		return name
+	}
+	return o.Quote(name)
+}
+
+// IsIdentifier returns true if the given string is a valid TypeScript identifier, e.g. a property
+// name that doesn't need quoting, or an enum member name.
+func IsIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !(unicode.IsLetter(r) || r == '_' || r == '$' || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return true
+}
+
+// Semicolon returns the terminator of statements and property signatures, which is empty if
+// OmitSemicolons is true.
+func (o RenderOptions) Semicolon() string {
+	if o.OmitSemicolons {
+		return ""
+	}
+	return ";"
+}
+
+// formatter renders TypeScript code as per a RenderOptions, and references type declarations by the
+// names returned by a nameFunc.
+type formatter struct {
+	// indent is the indentation of each nesting level.
+	indent string
+
+	// semicolon is the terminator of statements and property signatures, which may be empty.
+	semicolon string
+
+	// opts are the options the formatter was created for.
+	opts RenderOptions
+
+	// names returns the names by which type declarations are referenced.
+	names nameFunc
+}
+
+// newFormatter returns a formatter for the given options that references type declarations by the
+// names returned by the given function.
+func newFormatter(opts RenderOptions, names nameFunc) *formatter {
+	return &formatter{
+		indent:    opts.Indentation(),
+		semicolon: opts.Semicolon(),
+		opts:      opts,
+		names:     names,
+	}
+}
+
+// defaultFormatter returns the formatter used by the ToTypeScript() methods, which formats TypeScript
+// code as per the zero RenderOptions and references type declarations by their qualified names.
+func defaultFormatter() *formatter {
+	return newFormatter(RenderOptions{}, qualifiedName)
+}
+
+// quoted returns the given string enclosed in quotes.
+func (f *formatter) quoted(s string) string {
+	return f.opts.Quote(s)
+}
@@ -0,0 +1,249 @@
+package typescript
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TypeGuardName returns the qualified name of the type guard function generated by TypeGuard() for
+// the given type declaration, e.g. MyNamespace.isMyType.
+func TypeGuardName(typeDeclaration TypeDeclaration) string {
+	return typeGuardName(typeDeclaration, defaultFormatter())
+}
+
+// typeGuardName is like TypeGuardName, but based on the name by which the given formatter references
+// the given type declaration, e.g. "isJob" for "Job" in ES modules (see Module).
+func typeGuardName(typeDeclaration TypeDeclaration, f *formatter) string {
+	namespace, identifier := splitQualifiedName(f.names(typeDeclaration))
+	return makeQualifiedName(namespace, typeGuardIdentifier(identifier))
+}
+
+// typeGuardIdentifier returns the identifier of the type guard function for the type with the given
+// identifier, e.g. "isTurtle" for "Turtle", or "is_speed" for "speed".
+//
+// The case of the identifier is kept, such that types whose identifiers only differ in case (e.g.
+// "speed" and "Speed") have distinct type guards.
+func typeGuardIdentifier(identifier string) string {
+	if r, _ := utf8.DecodeRuneInString(identifier); unicode.IsUpper(r) {
+		return "is" + identifier
+	}
+	return "is_" + identifier
+}
+
+// TypeGuard returns a TypeScript user-defined type guard function for the given type declaration,
+// e.g. "export function isTurtle(x: unknown): x is Turtle { ... }", which checks at runtime whether
+// a value (e.g. the result of JSON.parse()) conforms to the declared type.
+//
+// Type guards call each other for any types referenced by the given type declaration. Therefore,
+// TypeGuard() should be called for all type declarations the given declaration depends on.
+//
+// Map types are checked by their values only, and properties not declared in an interface are
+// ignored.
+//
+// Type guards for generic type declarations take one type guard per type parameter, e.g.
+// "export function isPage<T>(x: unknown, isT: (x: unknown) => x is T): x is Page<T>".
+//
+// See https://www.typescriptlang.org/docs/handbook/advanced-types.html#user-defined-type-guards.
+func TypeGuard(typeDeclaration TypeDeclaration) string {
+	return TypeGuardWithOptions(typeDeclaration, RenderOptions{})
+}
+
+// TypeGuardWithOptions is like TypeGuard, but formatted as per the given options.
+func TypeGuardWithOptions(typeDeclaration TypeDeclaration, opts RenderOptions) string {
+	f := newFormatter(opts, qualifiedName)
+	namespace, _ := splitQualifiedName(typeDeclaration.QualifiedName())
+	return namespaced(namespace, typeGuard(typeDeclaration, f), f)
+}
+
+// typeGuard is like TypeGuard, but without any namespace blocks, and formatted by the given
+// formatter, which also determines the names by which type declarations (and their type guards) are
+// referenced.
+func typeGuard(typeDeclaration TypeDeclaration, f *formatter) string {
+	_, identifier := splitQualifiedName(typeDeclaration.QualifiedName())
+
+	var body string
+	switch typeDeclaration := typeDeclaration.(type) {
+	case *InterfaceDeclaration:
+		body = interfaceTypeGuardBody(typeDeclaration, f)
+	case *TypeAliasDeclaration:
+		body = fmt.Sprintf("%sreturn %s%s", f.indent, typeGuardExpression(typeDeclaration.Type, "x", 0, f), f.semicolon)
+	case *EnumDeclaration:
+		var checks []string
+		for _, member := range typeDeclaration.Members {
+			checks = append(checks, fmt.Sprintf("x === %s", member.Value.toTypeScript(f)))
+		}
+		if len(checks) == 0 {
+			checks = []string{"false"}
+		}
+		body = fmt.Sprintf("%sreturn %s%s", f.indent, strings.Join(checks, " || "), f.semicolon)
+	default:
+		panic(fmt.Sprintf("Unknown TypeScript type declaration: %T.", typeDeclaration))
+	}
+
+	typeParameters := TypeParameters(typeDeclaration)
+	parameters := []string{"x: unknown"}
+	for _, typeParameter := range typeParameters {
+		parameters = append(parameters, fmt.Sprintf("%s: (x: unknown) => x is %s", typeGuardIdentifier(typeParameter.Identifier), typeParameter.Identifier))
+	}
+	typeParameterList := typeParameterList(typeParameters)
+	return fmt.Sprintf("export function %s%s(%s): x is %s%s {\n%s\n}", typeGuardIdentifier(identifier), typeParameterList, strings.Join(parameters, ", "), f.names(typeDeclaration), typeParameterList, body)
+}
+
+// interfaceTypeGuardBody returns the body of the type guard function for the given interface.
+func interfaceTypeGuardBody(interfaceDeclaration *InterfaceDeclaration, f *formatter) string {
+	var checks []string
+	for _, prop := range interfaceDeclaration.Properties {
+		value := fmt.Sprintf("o[%s]", f.quoted(prop.Identifier))
+		check := typeGuardExpression(prop.Type, value, 0, f)
+		if check == "true" {
+			continue
+		}
+		if prop.Optional {
+			check = fmt.Sprintf("(%s === undefined || %s)", value, check)
+		} else if isUnionType(prop.Type) {
+			check = fmt.Sprintf("(%s)", check)
+		}
+		checks = append(checks, check)
+	}
+
+	indent := f.indent
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%sif (typeof x !== %s || x === null || Array.isArray(x)) {\n", indent, f.quoted("object")))
+	sb.WriteString(fmt.Sprintf("%s%sreturn false%s\n", indent, indent, f.semicolon))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+	if len(checks) == 0 {
+		sb.WriteString(fmt.Sprintf("%sreturn true%s", indent, f.semicolon))
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("%sconst o = x as Record<string, unknown>%s\n", indent, f.semicolon))
+	sb.WriteString(fmt.Sprintf("%sreturn (\n", indent))
+	sb.WriteString(indent + indent)
+	sb.WriteString(strings.Join(checks, " &&\n"+indent+indent))
+	sb.WriteString(fmt.Sprintf("\n%s)%s", indent, f.semicolon))
+	return sb.String()
+}
+
+// typeGuardExpression returns a TypeScript boolean expression that checks whether the given value
+// conforms to the given type. The depth is used to name the parameters of nested arrow functions.
+//
+// Expressions for union types are not parenthesized, so callers must parenthesize them if needed.
+func typeGuardExpression(t Type, value string, depth int, f *formatter) string {
+	switch t := t.(type) {
+	case BasicType:
+		switch t {
+		case Null:
+			return fmt.Sprintf("%s === null", value)
+		case Any:
+			return "true"
+		}
+		return fmt.Sprintf("typeof %s === %s", value, f.quoted(string(t)))
+	case *LiteralType:
+		return fmt.Sprintf("%s === %s", value, t.toTypeScript(f))
+	case *ArrayType:
+		item := itemIdentifier(depth + 1)
+		return fmt.Sprintf("(Array.isArray(%s) && %s.every((%s) => %s))", value, value, item, typeGuardExpression(t.ItemsType, item, depth+1, f))
+	case *TupleType:
+		checks := []string{fmt.Sprintf("Array.isArray(%s)", value), fmt.Sprintf("%s.length === %d", value, len(t.ElementTypes))}
+		for i, elementType := range t.ElementTypes {
+			check := typeGuardExpression(elementType, fmt.Sprintf("%s[%d]", value, i), depth, f)
+			if check == "true" {
+				continue
+			}
+			if isUnionType(elementType) {
+				check = fmt.Sprintf("(%s)", check)
+			}
+			checks = append(checks, check)
+		}
+		return fmt.Sprintf("(%s)", strings.Join(checks, " && "))
+	case *MapType:
+		item := itemIdentifier(depth + 1)
+		return fmt.Sprintf("(typeof %s === %s && %s !== null && !Array.isArray(%s) && Object.values(%s).every((%s) => %s))", value, f.quoted("object"), value, value, value, item, typeGuardExpression(t.ValueType, item, depth+1, f))
+	case UnionType:
+		return unionTypeGuardExpression(t, value, depth, f)
+	case *UnionType:
+		return unionTypeGuardExpression(*t, value, depth, f)
+	case *TypeReference:
+		return fmt.Sprintf("%s(%s)", typeGuardName(t.typeDeclaration, f), value)
+	case *TypeParameter:
+		return fmt.Sprintf("%s(%s)", typeGuardIdentifier(t.Identifier), value)
+	case *GenericTypeReference:
+		arguments := []string{value}
+		for _, typeArgument := range t.TypeArguments {
+			arguments = append(arguments, typeGuardFunction(typeArgument, depth, f))
+		}
+		return fmt.Sprintf("%s(%s)", typeGuardName(t.TypeReference.typeDeclaration, f), strings.Join(arguments, ", "))
+	}
+	panic(fmt.Sprintf("Unknown TypeScript type: %T.", t))
+}
+
+// itemIdentifier returns the name of the parameter of the arrow functions nested at the given depth
+// in type guards, e.g. "$v1". Go identifiers cannot contain "$", thus the parameter cannot shadow a
+// namespace or type guard, e.g. namespace "v1".
+func itemIdentifier(depth int) string {
+	return fmt.Sprintf("$v%d", depth)
+}
+
+// typeGuardFunction returns a TypeScript type guard function for the given type, to be passed as a
+// type argument to the type guard of a generic type. This is the name of the type guard for type
+// references and type parameters, or an arrow function for any other types, e.g.
+// "($v1: unknown): $v1 is string => typeof $v1 === 'string'".
+func typeGuardFunction(t Type, depth int, f *formatter) string {
+	switch t := t.(type) {
+	case *TypeReference:
+		if len(TypeParameters(t.typeDeclaration)) == 0 {
+			return typeGuardName(t.typeDeclaration, f)
+		}
+	case *TypeParameter:
+		return typeGuardIdentifier(t.Identifier)
+	}
+	item := itemIdentifier(depth + 1)
+	return fmt.Sprintf("(%s: unknown): %s is %s => %s", item, item, t.toTypeScript(f), typeGuardExpression(t, item, depth+1, f))
+}
+
+// unionTypeGuardExpression returns a TypeScript boolean expression that checks whether the given
+// value conforms to any of the types in the given union type. The expression is not parenthesized.
+func unionTypeGuardExpression(u UnionType, value string, depth int, f *formatter) string {
+	var checks []string
+	for _, t := range u.Types {
+		check := typeGuardExpression(t, value, depth, f)
+		if check == "true" {
+			return "true"
+		}
+		checks = append(checks, check)
+	}
+	if len(checks) == 0 {
+		return "false"
+	}
+	return strings.Join(checks, " || ")
+}
+
+// isUnionType returns true if the given type is a union type.
+func isUnionType(t Type) bool {
+	switch t.(type) {
+	case UnionType, *UnionType:
+		return true
+	}
+	return false
+}
+
+// splitQualifiedName splits a qualified TypeScript type name into its namespace and identifier.
+// This is the inverse of makeQualifiedName().
+func splitQualifiedName(qualifiedName string) (namespace, identifier string) {
+	if i := strings.LastIndex(qualifiedName, "."); i >= 0 {
+		return qualifiedName[:i], qualifiedName[i+1:]
+	}
+	return "", qualifiedName
+}
+
+// indent prefixes each non-empty line of the given text with the given indentation.
+func indent(text, indentation string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indentation + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
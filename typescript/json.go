@@ -0,0 +1,486 @@
+package typescript
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// jsonVersion is the version of the JSON encoding written by MarshalTypeDeclarations.
+const jsonVersion = 1
+
+// jsonTypeDeclarations is the JSON encoding of a set of type declarations.
+type jsonTypeDeclarations struct {
+	Version          int                   `json:"version"`
+	TypeDeclarations []jsonTypeDeclaration `json:"typeDeclarations"`
+}
+
+// Kinds of type declarations in the JSON encoding.
+const (
+	jsonInterface = "interface"
+	jsonTypeAlias = "typeAlias"
+	jsonEnum      = "enum"
+)
+
+// jsonTypeDeclaration is the JSON encoding of a TypeDeclaration. Fields that don't apply to its kind
+// are omitted.
+type jsonTypeDeclaration struct {
+	Kind           string           `json:"kind"`
+	Namespace      string           `json:"namespace,omitempty"`
+	Identifier     string           `json:"identifier"`
+	Doc            string           `json:"doc,omitempty"`
+	TypeParameters []string         `json:"typeParameters,omitempty"`
+	Properties     []jsonProperty   `json:"properties,omitempty"`
+	Type           *jsonType        `json:"type,omitempty"`
+	ConstObject    bool             `json:"constObject,omitempty"`
+	Members        []jsonEnumMember `json:"members,omitempty"`
+}
+
+// jsonProperty is the JSON encoding of a PropertySignature.
+type jsonProperty struct {
+	Identifier string    `json:"identifier"`
+	Type       *jsonType `json:"type"`
+	Optional   bool      `json:"optional,omitempty"`
+	Readonly   bool      `json:"readonly,omitempty"`
+	Doc        string    `json:"doc,omitempty"`
+}
+
+// jsonEnumMember is the JSON encoding of an EnumMember.
+type jsonEnumMember struct {
+	Identifier string    `json:"identifier"`
+	Value      *jsonType `json:"value"`
+}
+
+// Kinds of types in the JSON encoding.
+const (
+	jsonBasic                = "basic"
+	jsonLiteral              = "literal"
+	jsonArray                = "array"
+	jsonTuple                = "tuple"
+	jsonMap                  = "map"
+	jsonUnion                = "union"
+	jsonTypeReference        = "reference"
+	jsonTypeParameter        = "typeParameter"
+	jsonGenericTypeReference = "genericReference"
+)
+
+// jsonType is the JSON encoding of a Type. Fields that don't apply to its kind are omitted.
+type jsonType struct {
+	Kind string `json:"kind"`
+
+	// BasicType is set for basic and literal types.
+	BasicType string `json:"basicType,omitempty"`
+	Literal   string `json:"literal,omitempty"`
+
+	// Items is set for array types.
+	Items *jsonType `json:"items,omitempty"`
+
+	// Index and Value are set for map types.
+	Index *jsonType `json:"index,omitempty"`
+	Value *jsonType `json:"value,omitempty"`
+
+	// Types is set for union and tuple types.
+	Types []*jsonType `json:"types,omitempty"`
+
+	// Name is the qualified name of the referenced type declaration for type references, or the
+	// identifier of type parameters.
+	Name string `json:"name,omitempty"`
+
+	// TypeArguments is set for generic type references.
+	TypeArguments []*jsonType `json:"typeArguments,omitempty"`
+
+	// Readonly may be set for array, tuple and map types.
+	Readonly bool `json:"readonly,omitempty"`
+}
+
+// MarshalTypeDeclarations returns a stable JSON encoding of the given type declarations, e.g. to
+// cache them, to feed them to other tools, or to compare them across commits (see the compat
+// package). The type declarations can be loaded back via UnmarshalTypeDeclarations.
+//
+// References to type declarations are encoded by qualified name, thus the type declarations should
+// include all the type declarations they reference, as returned by go2ts.Go2TS.TypeDeclarations().
+func MarshalTypeDeclarations(typeDeclarations []TypeDeclaration) ([]byte, error) {
+	encoded := jsonTypeDeclarations{
+		Version:          jsonVersion,
+		TypeDeclarations: []jsonTypeDeclaration{},
+	}
+	for _, typeDeclaration := range typeDeclarations {
+		encoded.TypeDeclarations = append(encoded.TypeDeclarations, marshalTypeDeclaration(typeDeclaration))
+	}
+	return json.MarshalIndent(encoded, "", "  ")
+}
+
+// marshalTypeDeclaration returns the JSON encoding of the given type declaration.
+func marshalTypeDeclaration(typeDeclaration TypeDeclaration) jsonTypeDeclaration {
+	var encoded jsonTypeDeclaration
+	switch typeDeclaration := typeDeclaration.(type) {
+	case *InterfaceDeclaration:
+		encoded = jsonTypeDeclaration{
+			Kind:       jsonInterface,
+			Namespace:  typeDeclaration.Namespace,
+			Identifier: typeDeclaration.Identifier,
+			Doc:        typeDeclaration.Doc,
+		}
+		for _, prop := range typeDeclaration.Properties {
+			encoded.Properties = append(encoded.Properties, jsonProperty{
+				Identifier: prop.Identifier,
+				Type:       marshalType(prop.Type),
+				Optional:   prop.Optional,
+				Readonly:   prop.Readonly,
+				Doc:        prop.Doc,
+			})
+		}
+	case *TypeAliasDeclaration:
+		encoded = jsonTypeDeclaration{
+			Kind:       jsonTypeAlias,
+			Namespace:  typeDeclaration.Namespace,
+			Identifier: typeDeclaration.Identifier,
+			Doc:        typeDeclaration.Doc,
+			Type:       marshalType(typeDeclaration.Type),
+		}
+	case *EnumDeclaration:
+		encoded = jsonTypeDeclaration{
+			Kind:        jsonEnum,
+			Namespace:   typeDeclaration.Namespace,
+			Identifier:  typeDeclaration.Identifier,
+			Doc:         typeDeclaration.Doc,
+			ConstObject: typeDeclaration.Style == ConstObject,
+		}
+		for _, member := range typeDeclaration.Members {
+			encoded.Members = append(encoded.Members, jsonEnumMember{
+				Identifier: member.Identifier,
+				Value:      marshalType(member.Value),
+			})
+		}
+	default:
+		panic(fmt.Sprintf("Unknown TypeScript type declaration: %T.", typeDeclaration))
+	}
+
+	for _, typeParameter := range TypeParameters(typeDeclaration) {
+		encoded.TypeParameters = append(encoded.TypeParameters, typeParameter.Identifier)
+	}
+	return encoded
+}
+
+// marshalType returns the JSON encoding of the given type.
+func marshalType(t Type) *jsonType {
+	switch t := t.(type) {
+	case BasicType:
+		return &jsonType{Kind: jsonBasic, BasicType: string(t)}
+	case *LiteralType:
+		return &jsonType{Kind: jsonLiteral, BasicType: string(t.BasicType), Literal: t.Literal}
+	case *ArrayType:
+		return &jsonType{Kind: jsonArray, Items: marshalType(t.ItemsType), Readonly: t.Readonly}
+	case *TupleType:
+		encoded := &jsonType{Kind: jsonTuple, Readonly: t.Readonly}
+		for _, elementType := range t.ElementTypes {
+			encoded.Types = append(encoded.Types, marshalType(elementType))
+		}
+		return encoded
+	case *MapType:
+		// Index signature types are encoded with any type aliases resolved, as said type aliases might
+		// not be declared, e.g. if they are placeholders for Go map key types. See go2ts.Go2TS.
+		indexType := t.IndexType
+		if !t.IsMappedType() {
+			indexType = t.IndexSignatureType()
+		}
+		return &jsonType{Kind: jsonMap, Index: marshalType(indexType), Value: marshalType(t.ValueType), Readonly: t.Readonly}
+	case UnionType:
+		return marshalUnionType(t)
+	case *UnionType:
+		return marshalUnionType(*t)
+	case *TypeReference:
+		return &jsonType{Kind: jsonTypeReference, Name: t.typeDeclaration.QualifiedName()}
+	case *TypeParameter:
+		return &jsonType{Kind: jsonTypeParameter, Name: t.Identifier}
+	case *GenericTypeReference:
+		encoded := &jsonType{Kind: jsonGenericTypeReference, Name: t.TypeReference.typeDeclaration.QualifiedName()}
+		for _, typeArgument := range t.TypeArguments {
+			encoded.TypeArguments = append(encoded.TypeArguments, marshalType(typeArgument))
+		}
+		return encoded
+	}
+	panic(fmt.Sprintf("Unknown TypeScript type: %T.", t))
+}
+
+// marshalUnionType returns the JSON encoding of the given union type.
+func marshalUnionType(u UnionType) *jsonType {
+	encoded := &jsonType{Kind: jsonUnion}
+	for _, t := range u.Types {
+		encoded.Types = append(encoded.Types, marshalType(t))
+	}
+	return encoded
+}
+
+// UnmarshalTypeDeclarations loads the type declarations encoded by MarshalTypeDeclarations, with
+// references to type declarations resolved by qualified name, in the same order. The loaded type
+// declarations render the same TypeScript code as the encoded ones.
+//
+// Union types are always loaded as *UnionType, which is what Go2TS produces.
+//
+// It returns an error if the JSON encoding is invalid, e.g. a boolean literal other than true or
+// false, a map whose index type is neither a string nor a number, or an enum member that is neither
+// a string nor a number, if any type declarations are declared more than once, or if any
+// referenced type declarations are not declared.
+func UnmarshalTypeDeclarations(data []byte) ([]TypeDeclaration, error) {
+	var encoded jsonTypeDeclarations
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+	if encoded.Version != jsonVersion {
+		return nil, fmt.Errorf("unsupported JSON encoding version %d", encoded.Version)
+	}
+
+	// Declare all the type declarations first, such that references can be resolved regardless of
+	// their order.
+	typeDeclarations := []TypeDeclaration{}
+	byName := map[string]TypeDeclaration{}
+	for _, encodedTypeDeclaration := range encoded.TypeDeclarations {
+		var typeDeclaration TypeDeclaration
+		switch encodedTypeDeclaration.Kind {
+		case jsonInterface:
+			typeDeclaration = &InterfaceDeclaration{
+				Namespace:      encodedTypeDeclaration.Namespace,
+				Identifier:     encodedTypeDeclaration.Identifier,
+				TypeParameters: unmarshalTypeParameters(encodedTypeDeclaration.TypeParameters),
+				Doc:            encodedTypeDeclaration.Doc,
+			}
+		case jsonTypeAlias:
+			typeDeclaration = &TypeAliasDeclaration{
+				Namespace:      encodedTypeDeclaration.Namespace,
+				Identifier:     encodedTypeDeclaration.Identifier,
+				TypeParameters: unmarshalTypeParameters(encodedTypeDeclaration.TypeParameters),
+				Doc:            encodedTypeDeclaration.Doc,
+			}
+		case jsonEnum:
+			enumDeclaration := &EnumDeclaration{
+				Namespace:  encodedTypeDeclaration.Namespace,
+				Identifier: encodedTypeDeclaration.Identifier,
+				Doc:        encodedTypeDeclaration.Doc,
+			}
+			if encodedTypeDeclaration.ConstObject {
+				enumDeclaration.Style = ConstObject
+			}
+			typeDeclaration = enumDeclaration
+		default:
+			return nil, fmt.Errorf("unknown kind of TypeScript type declaration %q", encodedTypeDeclaration.Kind)
+		}
+
+		qualifiedName := typeDeclaration.QualifiedName()
+		if _, ok := byName[qualifiedName]; ok {
+			return nil, fmt.Errorf("TypeScript type %q is declared more than once", qualifiedName)
+		}
+		byName[qualifiedName] = typeDeclaration
+		typeDeclarations = append(typeDeclarations, typeDeclaration)
+	}
+
+	for i, encodedTypeDeclaration := range encoded.TypeDeclarations {
+		u := &unmarshaler{
+			typeDeclarations: byName,
+			typeParameters:   TypeParameters(typeDeclarations[i]),
+		}
+		var err error
+		switch typeDeclaration := typeDeclarations[i].(type) {
+		case *InterfaceDeclaration:
+			for _, encodedProp := range encodedTypeDeclaration.Properties {
+				prop := PropertySignature{
+					Identifier: encodedProp.Identifier,
+					Optional:   encodedProp.Optional,
+					Readonly:   encodedProp.Readonly,
+					Doc:        encodedProp.Doc,
+				}
+				if prop.Type, err = u.unmarshalType(encodedProp.Type); err != nil {
+					return nil, fmt.Errorf("property %s of %s: %w", prop.Identifier, typeDeclaration.QualifiedName(), err)
+				}
+				typeDeclaration.Properties = append(typeDeclaration.Properties, prop)
+			}
+		case *TypeAliasDeclaration:
+			if typeDeclaration.Type, err = u.unmarshalType(encodedTypeDeclaration.Type); err != nil {
+				return nil, fmt.Errorf("%s: %w", typeDeclaration.QualifiedName(), err)
+			}
+		case *EnumDeclaration:
+			for _, encodedMember := range encodedTypeDeclaration.Members {
+				value, err := u.unmarshalType(encodedMember.Value)
+				if err != nil {
+					return nil, fmt.Errorf("member %s of %s: %w", encodedMember.Identifier, typeDeclaration.QualifiedName(), err)
+				}
+				literal, ok := value.(*LiteralType)
+				if !ok {
+					return nil, fmt.Errorf("member %s of %s: value must be a literal type, got %q", encodedMember.Identifier, typeDeclaration.QualifiedName(), encodedMember.Value.Kind)
+				}
+				if typeDeclaration.Style == EnumKeyword && literal.BasicType != String && literal.BasicType != Number {
+					return nil, fmt.Errorf("member %s of %s: value must be a string or a number, got %q", encodedMember.Identifier, typeDeclaration.QualifiedName(), literal.BasicType)
+				}
+				typeDeclaration.Members = append(typeDeclaration.Members, EnumMember{Identifier: encodedMember.Identifier, Value: literal})
+			}
+		}
+	}
+
+	// Index types may reference type aliases declared later, thus they can only be checked once all
+	// the references are resolved.
+	for _, typeDeclaration := range typeDeclarations {
+		switch typeDeclaration := typeDeclaration.(type) {
+		case *InterfaceDeclaration:
+			for _, prop := range typeDeclaration.Properties {
+				if err := validateIndexTypes(prop.Type); err != nil {
+					return nil, fmt.Errorf("property %s of %s: %w", prop.Identifier, typeDeclaration.QualifiedName(), err)
+				}
+			}
+		case *TypeAliasDeclaration:
+			if err := validateIndexTypes(typeDeclaration.Type); err != nil {
+				return nil, fmt.Errorf("%s: %w", typeDeclaration.QualifiedName(), err)
+			}
+		}
+	}
+	return typeDeclarations, nil
+}
+
+// validateIndexTypes returns an error if the given type contains any map types that are neither
+// mapped types nor have a string or number index signature parameter type.
+func validateIndexTypes(t Type) error {
+	switch t := t.(type) {
+	case *ArrayType:
+		return validateIndexTypes(t.ItemsType)
+	case *TupleType:
+		for _, elementType := range t.ElementTypes {
+			if err := validateIndexTypes(elementType); err != nil {
+				return err
+			}
+		}
+	case *MapType:
+		if !t.IsMappedType() {
+			if indexType := t.IndexSignatureType().ToTypeScript(); indexType != "number" && indexType != "string" {
+				return fmt.Errorf("TypeScript type %q cannot be used as an index signature parameter type", indexType)
+			}
+		}
+		return validateIndexTypes(t.ValueType)
+	case *UnionType:
+		for _, unionType := range t.Types {
+			if err := validateIndexTypes(unionType); err != nil {
+				return err
+			}
+		}
+	case *GenericTypeReference:
+		for _, typeArgument := range t.TypeArguments {
+			if err := validateIndexTypes(typeArgument); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// unmarshalTypeParameters returns type parameters with the given identifiers.
+func unmarshalTypeParameters(identifiers []string) []*TypeParameter {
+	var typeParameters []*TypeParameter
+	for _, identifier := range identifiers {
+		typeParameters = append(typeParameters, &TypeParameter{Identifier: identifier})
+	}
+	return typeParameters
+}
+
+// unmarshaler loads the types of a type declaration from their JSON encoding.
+type unmarshaler struct {
+	// typeDeclarations maps the qualified names of all type declarations to said declarations.
+	typeDeclarations map[string]TypeDeclaration
+
+	// typeParameters holds the type parameters of the type declaration, which references to type
+	// parameters resolve to.
+	typeParameters []*TypeParameter
+}
+
+// unmarshalType returns the type encoded by the given JSON encoding.
+func (u *unmarshaler) unmarshalType(encoded *jsonType) (Type, error) {
+	if encoded == nil {
+		return nil, fmt.Errorf("missing type")
+	}
+
+	switch encoded.Kind {
+	case jsonBasic:
+		switch t := BasicType(encoded.BasicType); t {
+		case Boolean, Number, String, Null, Any:
+			return t, nil
+		}
+		return nil, fmt.Errorf("unknown basic type %q", encoded.BasicType)
+	case jsonLiteral:
+		switch t := BasicType(encoded.BasicType); t {
+		case Boolean:
+			if encoded.Literal != "true" && encoded.Literal != "false" {
+				return nil, fmt.Errorf("invalid boolean literal %q", encoded.Literal)
+			}
+		case Number:
+			if f, err := strconv.ParseFloat(encoded.Literal, 64); err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+				return nil, fmt.Errorf("invalid number literal %q", encoded.Literal)
+			}
+		case String:
+		default:
+			return nil, fmt.Errorf("unknown basic type %q of literal %q", encoded.BasicType, encoded.Literal)
+		}
+		return &LiteralType{BasicType: BasicType(encoded.BasicType), Literal: encoded.Literal}, nil
+	case jsonArray:
+		itemsType, err := u.unmarshalType(encoded.Items)
+		if err != nil {
+			return nil, err
+		}
+		return &ArrayType{ItemsType: itemsType, Readonly: encoded.Readonly}, nil
+	case jsonTuple:
+		tupleType := &TupleType{Readonly: encoded.Readonly}
+		for _, encodedType := range encoded.Types {
+			t, err := u.unmarshalType(encodedType)
+			if err != nil {
+				return nil, err
+			}
+			tupleType.ElementTypes = append(tupleType.ElementTypes, t)
+		}
+		return tupleType, nil
+	case jsonMap:
+		indexType, err := u.unmarshalType(encoded.Index)
+		if err != nil {
+			return nil, err
+		}
+		valueType, err := u.unmarshalType(encoded.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &MapType{IndexType: indexType, ValueType: valueType, Readonly: encoded.Readonly}, nil
+	case jsonUnion:
+		unionType := &UnionType{}
+		for _, encodedType := range encoded.Types {
+			t, err := u.unmarshalType(encodedType)
+			if err != nil {
+				return nil, err
+			}
+			unionType.Types = append(unionType.Types, t)
+		}
+		return unionType, nil
+	case jsonTypeReference:
+		typeDeclaration, ok := u.typeDeclarations[encoded.Name]
+		if !ok {
+			return nil, fmt.Errorf("TypeScript type %q is not declared", encoded.Name)
+		}
+		return &TypeReference{typeDeclaration: typeDeclaration}, nil
+	case jsonTypeParameter:
+		for _, typeParameter := range u.typeParameters {
+			if typeParameter.Identifier == encoded.Name {
+				return typeParameter, nil
+			}
+		}
+		return nil, fmt.Errorf("type parameter %q is not declared", encoded.Name)
+	case jsonGenericTypeReference:
+		typeDeclaration, ok := u.typeDeclarations[encoded.Name]
+		if !ok {
+			return nil, fmt.Errorf("TypeScript type %q is not declared", encoded.Name)
+		}
+		genericTypeReference := &GenericTypeReference{TypeReference: &TypeReference{typeDeclaration: typeDeclaration}}
+		for _, encodedType := range encoded.TypeArguments {
+			t, err := u.unmarshalType(encodedType)
+			if err != nil {
+				return nil, err
+			}
+			genericTypeReference.TypeArguments = append(genericTypeReference.TypeArguments, t)
+		}
+		return genericTypeReference, nil
+	}
+	return nil, fmt.Errorf("unknown kind of TypeScript type %q", encoded.Kind)
+}
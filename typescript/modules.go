@@ -0,0 +1,275 @@
+package typescript
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Module represents a TypeScript ES module, i.e. a .ts file, that declares a group of type
+// declarations. References to type declarations in other modules are imported, e.g.
+// "import type { Job } from './api/v1';".
+//
+// Namespaces are ignored within modules, i.e. modules take their place, so the identifiers of all
+// the type declarations in a module must be unique. Imported type declarations are renamed with a
+// numeric suffix if their identifiers are already used in the importing module, e.g.
+// "import type { Job as Job2 } from './api/v2';".
+//
+// See https://www.typescriptlang.org/docs/handbook/modules/introduction.html.
+type Module struct {
+	// Path is the path of the module relative to the root directory of all modules, without the .ts
+	// extension, e.g. "api/v1".
+	Path string
+
+	// TypeDeclarations holds the type declarations in the module, in the order they are rendered.
+	TypeDeclarations []TypeDeclaration
+
+	// TypeGuards determines whether ToTypeScript() also renders a type guard function for each type
+	// declaration (see TypeGuard()), after all the type declarations.
+	TypeGuards bool
+
+	// modules maps the qualified names of all the type declarations, including those in other
+	// modules, to their modules.
+	modules map[string]*Module
+}
+
+// Modules groups the given type declarations into ES modules, as per the module paths returned by
+// the given function, e.g. "api/v1". Type declarations keep their relative order within each module,
+// and are matched to the type declarations referenced by other modules by qualified name.
+//
+// Modules are returned such that any modules a given module imports precede it, unless they import
+// each other. Types are imported via "import type" statements, which are erased at compile time, and
+// type guard functions only call each other at runtime, so modules that import each other are safe.
+func Modules(typeDeclarations []TypeDeclaration, modulePath func(TypeDeclaration) string) []*Module {
+	modulesByPath := map[string]*Module{}
+	modules := map[string]*Module{}
+	var modulesInOrder []*Module
+	for _, typeDeclaration := range typeDeclarations {
+		p := modulePath(typeDeclaration)
+		module, ok := modulesByPath[p]
+		if !ok {
+			module = &Module{
+				Path:    p,
+				modules: modules,
+			}
+			modulesByPath[p] = module
+			modulesInOrder = append(modulesInOrder, module)
+		}
+		module.TypeDeclarations = append(module.TypeDeclarations, typeDeclaration)
+		modules[typeDeclaration.QualifiedName()] = module
+	}
+
+	// Sort the modules topologically, visiting them in order of appearance, and ignoring any imports
+	// that would form a cycle.
+	var sorted []*Module
+	visited := map[*Module]bool{}
+	var visit func(module *Module)
+	visit = func(module *Module) {
+		if visited[module] {
+			return
+		}
+		visited[module] = true
+		for _, typeDeclaration := range module.importedTypeDeclarations(false) {
+			visit(modules[typeDeclaration.QualifiedName()])
+		}
+		sorted = append(sorted, module)
+	}
+	for _, module := range modulesInOrder {
+		visit(module)
+	}
+	return sorted
+}
+
+// ToTypeScript returns the TypeScript code of the module, i.e. any import statements followed by
+// the type declarations and, if enabled, their type guard functions, separated by blank lines.
+func (m *Module) ToTypeScript() string {
+	return m.ToTypeScriptWithOptions(RenderOptions{})
+}
+
+// ToTypeScriptWithOptions is like ToTypeScript, but formatted as per the given options.
+func (m *Module) ToTypeScriptWithOptions(opts RenderOptions) string {
+	names := m.names()
+	f := newFormatter(opts, func(typeDeclaration TypeDeclaration) string {
+		return names[typeDeclaration.QualifiedName()]
+	})
+
+	var statements []string
+	if imports := m.imports(names, f); imports != "" {
+		statements = append(statements, imports)
+	}
+	for _, typeDeclaration := range m.TypeDeclarations {
+		statements = append(statements, typeDeclaration.declarationToTypeScript(f))
+	}
+	if m.TypeGuards {
+		for _, typeDeclaration := range m.TypeDeclarations {
+			statements = append(statements, typeGuard(typeDeclaration, f))
+		}
+	}
+	return strings.Join(statements, "\n\n")
+}
+
+// names returns the names by which the type declarations in the module, and those it imports, are
+// referenced within the module, indexed by qualified name.
+func (m *Module) names() map[string]string {
+	names := map[string]string{}
+	taken := map[string]bool{}
+	for _, typeDeclaration := range m.TypeDeclarations {
+		_, identifier := splitQualifiedName(typeDeclaration.QualifiedName())
+		names[typeDeclaration.QualifiedName()] = identifier
+		taken[identifier] = true
+	}
+	for _, typeDeclaration := range m.importedTypeDeclarations(false) {
+		_, identifier := splitQualifiedName(typeDeclaration.QualifiedName())
+		name := identifier
+		for i := 2; taken[name]; i++ {
+			name = fmt.Sprintf("%s%d", identifier, i)
+		}
+		names[typeDeclaration.QualifiedName()] = name
+		taken[name] = true
+	}
+	return names
+}
+
+// imports returns the import statements of the module, one per line, given the names by which the
+// imported type declarations are referenced within the module (see names()), formatted by the given
+// formatter. Imports are sorted by module path and name.
+func (m *Module) imports(names map[string]string, f *formatter) string {
+	typeImports := map[*Module][]string{}
+	for _, typeDeclaration := range m.importedTypeDeclarations(false) {
+		_, identifier := splitQualifiedName(typeDeclaration.QualifiedName())
+		module := m.modules[typeDeclaration.QualifiedName()]
+		typeImports[module] = append(typeImports[module], importSpecifier(identifier, names[typeDeclaration.QualifiedName()]))
+	}
+	typeGuardImports := map[*Module][]string{}
+	if m.TypeGuards {
+		for _, typeDeclaration := range m.importedTypeDeclarations(true) {
+			_, identifier := splitQualifiedName(typeDeclaration.QualifiedName())
+			module := m.modules[typeDeclaration.QualifiedName()]
+			typeGuardImports[module] = append(typeGuardImports[module], importSpecifier(typeGuardIdentifier(identifier), typeGuardIdentifier(names[typeDeclaration.QualifiedName()])))
+		}
+	}
+
+	var modules []*Module
+	for module := range typeImports {
+		modules = append(modules, module)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Path < modules[j].Path
+	})
+
+	var lines []string
+	for _, module := range modules {
+		importPath := f.quoted(relativeImportPath(m.Path, module.Path))
+		sort.Strings(typeImports[module])
+		lines = append(lines, fmt.Sprintf("import type { %s } from %s%s", strings.Join(typeImports[module], ", "), importPath, f.semicolon))
+		if specifiers := typeGuardImports[module]; len(specifiers) > 0 {
+			sort.Strings(specifiers)
+			lines = append(lines, fmt.Sprintf("import { %s } from %s%s", strings.Join(specifiers, ", "), importPath, f.semicolon))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// importedTypeDeclarations returns the type declarations in other modules referenced by the type
+// declarations in the module, in order of appearance. If typeGuards is true, it returns the type
+// declarations whose type guard functions are called by the type guards of the module instead.
+//
+// Type declarations are matched by qualified name, and it panics if a referenced type declaration
+// does not belong to any module.
+func (m *Module) importedTypeDeclarations(typeGuards bool) []TypeDeclaration {
+	var imported []TypeDeclaration
+	seen := map[string]bool{}
+	for _, typeDeclaration := range m.TypeDeclarations {
+		visitTypeReferences(typeDeclaration, typeGuards, func(referenced TypeDeclaration) {
+			qualifiedName := referenced.QualifiedName()
+			module, ok := m.modules[qualifiedName]
+			if !ok {
+				panic(fmt.Sprintf("TypeScript type %s is not declared in any module.", qualifiedName))
+			}
+			if module == m || seen[qualifiedName] {
+				return
+			}
+			seen[qualifiedName] = true
+			imported = append(imported, referenced)
+		})
+	}
+	return imported
+}
+
+// visitTypeReferences calls the given function for each type declaration referenced by the given
+// type declaration, in order of appearance. If typeGuards is true, only the type declarations whose
+// type guard functions are called by the type guard of the given type declaration are visited.
+func visitTypeReferences(typeDeclaration TypeDeclaration, typeGuards bool, visit func(TypeDeclaration)) {
+	var visitType func(t Type)
+	visitType = func(t Type) {
+		switch t := t.(type) {
+		case *ArrayType:
+			visitType(t.ItemsType)
+		case *TupleType:
+			for _, t := range t.ElementTypes {
+				visitType(t)
+			}
+		case *MapType:
+			// Only mapped types reference their index types, and type guards ignore them.
+			if t.IsMappedType() && !typeGuards {
+				visitType(t.IndexType)
+			}
+			visitType(t.ValueType)
+		case UnionType:
+			for _, t := range t.Types {
+				visitType(t)
+			}
+		case *UnionType:
+			for _, t := range t.Types {
+				visitType(t)
+			}
+		case *TypeReference:
+			visit(t.typeDeclaration)
+		case *GenericTypeReference:
+			visit(t.TypeReference.typeDeclaration)
+			for _, t := range t.TypeArguments {
+				visitType(t)
+			}
+		}
+	}
+
+	switch typeDeclaration := typeDeclaration.(type) {
+	case *InterfaceDeclaration:
+		for _, prop := range typeDeclaration.Properties {
+			visitType(prop.Type)
+		}
+	case *TypeAliasDeclaration:
+		visitType(typeDeclaration.Type)
+	}
+}
+
+// importSpecifier returns an import specifier for the given identifier, which is renamed if the
+// given name differs from it, e.g. "Job as Job2".
+func importSpecifier(identifier, name string) string {
+	if identifier == name {
+		return identifier
+	}
+	return fmt.Sprintf("%s as %s", identifier, name)
+}
+
+// relativeImportPath returns the path by which the module with the given path is imported from the
+// module at the given path, e.g. "../jobs" for "api/jobs" from "api/v1/users".
+func relativeImportPath(from, to string) string {
+	var fromDirs []string
+	if dir := path.Dir(from); dir != "." {
+		fromDirs = strings.Split(dir, "/")
+	}
+	toElems := strings.Split(to, "/")
+
+	common := 0
+	for common < len(fromDirs) && common < len(toElems)-1 && fromDirs[common] == toElems[common] {
+		common++
+	}
+
+	relative := strings.Join(toElems[common:], "/")
+	if common == len(fromDirs) {
+		return "./" + relative
+	}
+	return strings.Repeat("../", len(fromDirs)-common) + relative
+}
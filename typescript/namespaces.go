@@ -0,0 +1,139 @@
+package typescript
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Namespace groups TypeScript code (e.g. type declarations) by namespace, such that all the code in
+// a given namespace is rendered in a single namespace block. Nested namespaces, e.g. "api.v1", are
+// rendered as nested namespace blocks, e.g.:
+//
+//	export namespace api {
+//		export namespace v1 {
+//			export interface Job {
+//				Name: string;
+//			}
+//
+//			export type JobID = string;
+//		}
+//	}
+//
+// The zero value is the global namespace, to which code can be added with the Add* methods.
+//
+// See https://www.typescriptlang.org/docs/handbook/namespaces.html.
+type Namespace struct {
+	// Identifier is the identifier of the namespace, e.g. "v1" for "api.v1", or empty for the global
+	// namespace.
+	Identifier string
+
+	// statements holds the code and nested namespaces in the namespace, in the order they were added.
+	statements []namespaceStatement
+
+	// namespaces maps the identifiers of the nested namespaces to said namespaces.
+	namespaces map[string]*Namespace
+}
+
+// namespaceStatement is either a snippet of TypeScript code, a type declaration, a type guard
+// function or a nested namespace. Type declarations and type guards are rendered lazily, which
+// allows rendering them with different RenderOptions.
+type namespaceStatement struct {
+	code            string
+	typeDeclaration TypeDeclaration
+	typeGuard       bool
+	namespace       *Namespace
+}
+
+// Add adds the given TypeScript code to the given nested namespace, e.g. "api.v1", or to this
+// namespace if the given namespace is empty. Code added to the same namespace is rendered in the
+// same namespace block, which appears where code was first added to said namespace.
+func (n *Namespace) Add(namespace, code string) {
+	n.add(namespace, namespaceStatement{code: code})
+}
+
+// add adds the given statement to the given nested namespace, or to this namespace if the given
+// namespace is empty. See Add().
+func (n *Namespace) add(namespace string, statement namespaceStatement) {
+	if namespace == "" {
+		n.statements = append(n.statements, statement)
+		return
+	}
+
+	identifier, rest, _ := strings.Cut(namespace, ".")
+	nested, ok := n.namespaces[identifier]
+	if !ok {
+		if n.namespaces == nil {
+			n.namespaces = map[string]*Namespace{}
+		}
+		nested = &Namespace{Identifier: identifier}
+		n.namespaces[identifier] = nested
+		n.statements = append(n.statements, namespaceStatement{namespace: nested})
+	}
+	nested.add(rest, statement)
+}
+
+// AddTypeDeclaration adds the given type declaration to its namespace, relative to this namespace.
+func (n *Namespace) AddTypeDeclaration(typeDeclaration TypeDeclaration) {
+	namespace, _ := splitQualifiedName(typeDeclaration.QualifiedName())
+	n.add(namespace, namespaceStatement{typeDeclaration: typeDeclaration})
+}
+
+// AddTypeGuard adds the type guard function for the given type declaration (see TypeGuard()) to the
+// namespace of said type declaration, relative to this namespace.
+func (n *Namespace) AddTypeGuard(typeDeclaration TypeDeclaration) {
+	namespace, _ := splitQualifiedName(typeDeclaration.QualifiedName())
+	n.add(namespace, namespaceStatement{typeDeclaration: typeDeclaration, typeGuard: true})
+}
+
+// Statements returns the TypeScript code in the namespace, with nested namespaces rendered as
+// namespace blocks, in the order they were added.
+func (n *Namespace) Statements() []string {
+	return n.statementsToTypeScript(defaultFormatter())
+}
+
+// ToTypeScript returns the TypeScript code in the namespace, separated by blank lines, enclosed in a
+// namespace block unless this is the global namespace.
+func (n *Namespace) ToTypeScript() string {
+	return n.toTypeScript(defaultFormatter())
+}
+
+// ToTypeScriptWithOptions is like ToTypeScript, but formatted as per the given options. Any code
+// added via Add() is rendered as is, except for its indentation within namespace blocks.
+func (n *Namespace) ToTypeScriptWithOptions(opts RenderOptions) string {
+	return n.toTypeScript(newFormatter(opts, qualifiedName))
+}
+
+// toTypeScript is like ToTypeScript, but formatted by the given formatter.
+func (n *Namespace) toTypeScript(f *formatter) string {
+	code := strings.Join(n.statementsToTypeScript(f), "\n\n")
+	if n.Identifier == "" {
+		return code
+	}
+	return fmt.Sprintf("export namespace %s {\n%s\n}", n.Identifier, indent(code, f.indent))
+}
+
+// statementsToTypeScript is like Statements, but formatted by the given formatter.
+func (n *Namespace) statementsToTypeScript(f *formatter) []string {
+	var statements []string
+	for _, statement := range n.statements {
+		switch {
+		case statement.namespace != nil:
+			statements = append(statements, statement.namespace.toTypeScript(f))
+		case statement.typeGuard:
+			statements = append(statements, typeGuard(statement.typeDeclaration, f))
+		case statement.typeDeclaration != nil:
+			statements = append(statements, statement.typeDeclaration.declarationToTypeScript(f))
+		default:
+			statements = append(statements, statement.code)
+		}
+	}
+	return statements
+}
+
+// namespaced returns the given TypeScript code enclosed in the namespace blocks of the given
+// namespace, indented by the given formatter, or as is if the namespace is empty.
+func namespaced(namespace, code string, f *formatter) string {
+	var global Namespace
+	global.Add(namespace, code)
+	return global.toTypeScript(f)
+}
@@ -10,7 +10,7 @@
 // extend Go2TS in the future with support for additional types, new features, etc.
 //
 // The "root" type in this package is the TypeDeclaration interface, which is implemented by the
-// InterfaceDeclaration and TypeAliasDeclaration structs.
+// InterfaceDeclaration, TypeAliasDeclaration and EnumDeclaration structs.
 //
 // The names of the primitives in this package are loosely based on the names and terms used in the
 // TypeScript AST. Recommended links:
@@ -29,9 +29,26 @@ type Type interface {
 	// is invalid.
 	ToTypeScript() string
 
+	// ToTypeScriptWithOptions is like ToTypeScript, but formatted as per the given options.
+	ToTypeScriptWithOptions(opts RenderOptions) string
+
+	// toTypeScript is like ToTypeScript, but formatted by the given formatter, which also determines
+	// the names by which type declarations are referenced, e.g. in ES modules (see Module).
+	toTypeScript(f *formatter) string
+
 	isType()
 }
 
+// nameFunc returns the name by which the given type declaration is referenced, e.g. its qualified
+// name.
+type nameFunc func(TypeDeclaration) string
+
+// qualifiedName is the nameFunc that references type declarations by their qualified names, which is
+// what ToTypeScript() does.
+func qualifiedName(typeDeclaration TypeDeclaration) string {
+	return typeDeclaration.QualifiedName()
+}
+
 ///////////////
 // BasicType //
 ///////////////
@@ -61,6 +78,12 @@ const (
 // ToTypeScript implements the Type interface.
 func (b BasicType) ToTypeScript() string { return string(b) }
 
+// ToTypeScriptWithOptions implements the Type interface.
+func (b BasicType) ToTypeScriptWithOptions(RenderOptions) string { return b.ToTypeScript() }
+
+// toTypeScript implements the Type interface.
+func (b BasicType) toTypeScript(*formatter) string { return b.ToTypeScript() }
+
 // isType implements the Type interface.
 func (b BasicType) isType() {}
 
@@ -80,6 +103,16 @@ type LiteralType struct {
 
 // ToTypeScript implements the Type interface.
 func (l *LiteralType) ToTypeScript() string {
+	return l.toTypeScript(defaultFormatter())
+}
+
+// ToTypeScriptWithOptions implements the Type interface.
+func (l *LiteralType) ToTypeScriptWithOptions(opts RenderOptions) string {
+	return l.toTypeScript(newFormatter(opts, qualifiedName))
+}
+
+// toTypeScript implements the Type interface.
+func (l *LiteralType) toTypeScript(f *formatter) string {
 	switch l.BasicType {
 	case Boolean:
 		if l.Literal != "true" && l.Literal != "false" {
@@ -89,7 +122,7 @@ func (l *LiteralType) ToTypeScript() string {
 	case Number:
 		return l.Literal
 	case String:
-		return fmt.Sprintf(`'%s'`, l.Literal)
+		return f.quoted(l.Literal)
 	}
 	panic(fmt.Sprintf(`Invalid basic type: %q`, l.BasicType))
 }
@@ -106,15 +139,41 @@ var _ Type = (*LiteralType)(nil)
 // ArrayType represents a TypeScript array type such as string[], MyType[], etc.
 type ArrayType struct {
 	ItemsType Type
+
+	// Readonly determines whether the array is rendered as a readonly array, e.g. readonly string[].
+	//
+	// See https://www.typescriptlang.org/docs/handbook/2/objects.html#the-readonlyarray-type.
+	Readonly bool
 }
 
 // ToTypeScript implements the Type interface.
 func (a *ArrayType) ToTypeScript() string {
+	return a.toTypeScript(defaultFormatter())
+}
+
+// ToTypeScriptWithOptions implements the Type interface.
+func (a *ArrayType) ToTypeScriptWithOptions(opts RenderOptions) string {
+	return a.toTypeScript(newFormatter(opts, qualifiedName))
+}
+
+// toTypeScript implements the Type interface.
+func (a *ArrayType) toTypeScript(f *formatter) string {
 	fmtStr := "%s[]"
 	if _, ok := a.ItemsType.(*UnionType); ok {
 		fmtStr = "(%s)[]"
 	}
-	return fmt.Sprintf(fmtStr, a.ItemsType.ToTypeScript())
+	if itemsType, ok := a.ItemsType.(*ArrayType); ok && itemsType.Readonly {
+		// Without parentheses, "readonly string[][]" would be a readonly array of mutable arrays.
+		fmtStr = "(%s)[]"
+	}
+	if itemsType, ok := a.ItemsType.(*TupleType); ok && itemsType.Readonly {
+		// Likewise, "readonly [string][]" would be a readonly array of mutable tuples.
+		fmtStr = "(%s)[]"
+	}
+	if a.Readonly {
+		fmtStr = "readonly " + fmtStr
+	}
+	return fmt.Sprintf(fmtStr, a.ItemsType.toTypeScript(f))
 }
 
 // isType implements the Type interface.
@@ -122,25 +181,144 @@ func (a *ArrayType) isType() {}
 
 var _ Type = (*ArrayType)(nil)
 
+///////////////
+// TupleType //
+///////////////
+
+// TupleType represents a TypeScript tuple type, i.e. an array with a fixed number of elements, such
+// as [number, number, number].
+//
+// See https://www.typescriptlang.org/docs/handbook/2/objects.html#tuple-types.
+type TupleType struct {
+	ElementTypes []Type
+
+	// Readonly determines whether the tuple is rendered as a readonly tuple, e.g.
+	// readonly [number, number].
+	Readonly bool
+}
+
+// ToTypeScript implements the Type interface.
+func (t *TupleType) ToTypeScript() string {
+	return t.toTypeScript(defaultFormatter())
+}
+
+// ToTypeScriptWithOptions implements the Type interface.
+func (t *TupleType) ToTypeScriptWithOptions(opts RenderOptions) string {
+	return t.toTypeScript(newFormatter(opts, qualifiedName))
+}
+
+// toTypeScript implements the Type interface.
+func (t *TupleType) toTypeScript(f *formatter) string {
+	elementTypes := make([]string, 0, len(t.ElementTypes))
+	for _, elementType := range t.ElementTypes {
+		elementTypes = append(elementTypes, elementType.toTypeScript(f))
+	}
+	tuple := "[" + strings.Join(elementTypes, ", ") + "]"
+	if t.Readonly {
+		tuple = "readonly " + tuple
+	}
+	return tuple
+}
+
+// isType implements the Type interface.
+func (t *TupleType) isType() {}
+
+var _ Type = (*TupleType)(nil)
+
 /////////////
 // MapType //
 /////////////
 
 // MapType represents a TypeScript type that describes an object used as a dictionary of key/value
 // pairs, e.g. { [key: string]: MyStruct }.
+//
+// The IndexType can be "string", "number", or a reference to a type declaration. References to union
+// types of literals or to enums are rendered as mapped types, e.g. { [key in Direction]?: MyStruct },
+// where all keys are optional. References to type aliases for "string" or "number" are rendered as
+// index signatures of said types, because index signature parameter types cannot be type aliases.
+//
+// Readonly maps are rendered via the Readonly and Record utility types, e.g.
+// Readonly<Record<string, MyStruct>>, or Readonly<Partial<Record<Direction, MyStruct>>> for mapped
+// types.
+//
+// See https://www.typescriptlang.org/docs/handbook/2/mapped-types.html.
 type MapType struct {
 	IndexType Type
 	ValueType Type
+
+	// Readonly determines whether the map is rendered as a readonly object type.
+	Readonly bool
 }
 
 // ToTypeScript implements the Type interface.
 func (m *MapType) ToTypeScript() string {
-	indexTypeToTS := m.IndexType.ToTypeScript()
+	return m.toTypeScript(defaultFormatter())
+}
+
+// ToTypeScriptWithOptions implements the Type interface.
+func (m *MapType) ToTypeScriptWithOptions(opts RenderOptions) string {
+	return m.toTypeScript(newFormatter(opts, qualifiedName))
+}
+
+// toTypeScript implements the Type interface.
+func (m *MapType) toTypeScript(f *formatter) string {
+	if m.IsMappedType() {
+		if m.Readonly {
+			return fmt.Sprintf("Readonly<Partial<Record<%s, %s>>>", m.IndexType.toTypeScript(f), m.ValueType.toTypeScript(f))
+		}
+		return fmt.Sprintf("{ [key in %s]?: %s }", m.IndexType.toTypeScript(f), m.ValueType.toTypeScript(f))
+	}
+
+	indexTypeToTS := m.IndexSignatureType().ToTypeScript()
 	if indexTypeToTS != "number" && indexTypeToTS != "string" {
 		panic(fmt.Sprintf("TypeScript type %q cannot be used as an index signature parameter type.", indexTypeToTS))
 	}
 
-	return fmt.Sprintf("{ [key: %s]: %s }", indexTypeToTS, m.ValueType.ToTypeScript())
+	if m.Readonly {
+		return fmt.Sprintf("Readonly<Record<%s, %s>>", indexTypeToTS, m.ValueType.toTypeScript(f))
+	}
+	return fmt.Sprintf("{ [key: %s]: %s }", indexTypeToTS, m.ValueType.toTypeScript(f))
+}
+
+// IsMappedType returns true if the map is rendered as a mapped type, i.e. if its IndexType is a
+// reference to a union type of literals or to an enum.
+func (m *MapType) IsMappedType() bool {
+	indexType := m.IndexType
+	for {
+		typeReference, ok := indexType.(*TypeReference)
+		if !ok {
+			return false
+		}
+		switch typeDeclaration := typeReference.typeDeclaration.(type) {
+		case *EnumDeclaration:
+			return true
+		case *TypeAliasDeclaration:
+			if isLiteralUnion(typeDeclaration.Type) {
+				return true
+			}
+			indexType = typeDeclaration.Type
+		default:
+			return false
+		}
+	}
+}
+
+// IndexSignatureType returns the type of the index signature parameter of the map, i.e. its
+// IndexType with any references to type aliases resolved. Not applicable to mapped types (see
+// IsMappedType()).
+func (m *MapType) IndexSignatureType() Type {
+	indexType := m.IndexType
+	for {
+		typeReference, ok := indexType.(*TypeReference)
+		if !ok {
+			return indexType
+		}
+		typeAliasDeclaration, ok := typeReference.typeDeclaration.(*TypeAliasDeclaration)
+		if !ok {
+			return indexType
+		}
+		indexType = typeAliasDeclaration.Type
+	}
 }
 
 // isType implements the Type interface.
@@ -159,9 +337,19 @@ type UnionType struct {
 
 // ToTypeScript implements the Type interface.
 func (u UnionType) ToTypeScript() string {
+	return u.toTypeScript(defaultFormatter())
+}
+
+// ToTypeScriptWithOptions implements the Type interface.
+func (u UnionType) ToTypeScriptWithOptions(opts RenderOptions) string {
+	return u.toTypeScript(newFormatter(opts, qualifiedName))
+}
+
+// toTypeScript implements the Type interface.
+func (u UnionType) toTypeScript(f *formatter) string {
 	tsTypes := []string{}
 	for _, t := range u.Types {
-		tsTypes = append(tsTypes, t.ToTypeScript())
+		tsTypes = append(tsTypes, t.toTypeScript(f))
 	}
 	return strings.Join(tsTypes, " | ")
 }
@@ -181,9 +369,24 @@ type TypeReference struct {
 	typeDeclaration TypeDeclaration
 }
 
+// TypeDeclaration returns the referenced type declaration.
+func (t *TypeReference) TypeDeclaration() TypeDeclaration {
+	return t.typeDeclaration
+}
+
 // ToTypeScript implements the Type interface.
 func (t *TypeReference) ToTypeScript() string {
-	return t.typeDeclaration.QualifiedName()
+	return t.toTypeScript(defaultFormatter())
+}
+
+// ToTypeScriptWithOptions implements the Type interface.
+func (t *TypeReference) ToTypeScriptWithOptions(opts RenderOptions) string {
+	return t.toTypeScript(newFormatter(opts, qualifiedName))
+}
+
+// toTypeScript implements the Type interface.
+func (t *TypeReference) toTypeScript(f *formatter) string {
+	return f.names(t.typeDeclaration)
 }
 
 // isType implements the Type interface.
@@ -191,6 +394,68 @@ func (t *TypeReference) isType() {}
 
 var _ Type = (*TypeReference)(nil)
 
+///////////////////
+// TypeParameter //
+///////////////////
+
+// TypeParameter represents a type parameter of a generic type declaration, e.g. the T in
+// interface Page<T> { Items: T[]; }, and can be used as a Type within said declaration.
+type TypeParameter struct {
+	Identifier string
+}
+
+// ToTypeScript implements the Type interface.
+func (t *TypeParameter) ToTypeScript() string {
+	return t.Identifier
+}
+
+// ToTypeScriptWithOptions implements the Type interface.
+func (t *TypeParameter) ToTypeScriptWithOptions(RenderOptions) string { return t.ToTypeScript() }
+
+// toTypeScript implements the Type interface.
+func (t *TypeParameter) toTypeScript(*formatter) string { return t.ToTypeScript() }
+
+// isType implements the Type interface.
+func (t *TypeParameter) isType() {}
+
+var _ Type = (*TypeParameter)(nil)
+
+//////////////////////////
+// GenericTypeReference //
+//////////////////////////
+
+// GenericTypeReference represents a reference to a generic type declaration with the given type
+// arguments, e.g. Page<Item>. There must be one type argument per type parameter of the referenced
+// declaration.
+type GenericTypeReference struct {
+	TypeReference *TypeReference
+	TypeArguments []Type
+}
+
+// ToTypeScript implements the Type interface.
+func (g *GenericTypeReference) ToTypeScript() string {
+	return g.toTypeScript(defaultFormatter())
+}
+
+// ToTypeScriptWithOptions implements the Type interface.
+func (g *GenericTypeReference) ToTypeScriptWithOptions(opts RenderOptions) string {
+	return g.toTypeScript(newFormatter(opts, qualifiedName))
+}
+
+// toTypeScript implements the Type interface.
+func (g *GenericTypeReference) toTypeScript(f *formatter) string {
+	typeArguments := []string{}
+	for _, t := range g.TypeArguments {
+		typeArguments = append(typeArguments, t.toTypeScript(f))
+	}
+	return fmt.Sprintf("%s<%s>", g.TypeReference.toTypeScript(f), strings.Join(typeArguments, ", "))
+}
+
+// isType implements the Type interface.
+func (g *GenericTypeReference) isType() {}
+
+var _ Type = (*GenericTypeReference)(nil)
+
 /////////////////////
 // TypeDeclaration //
 /////////////////////
@@ -204,9 +469,18 @@ type TypeDeclaration interface {
 	// QualifiedName returns the qualified name of the declared type, e.g. MyNamespace.MyType.
 	QualifiedName() string
 
-	// ToTypeScript converts the TypeDeclaration to valid TypeScript.
+	// ToTypeScript converts the TypeDeclaration to valid TypeScript, including any namespace blocks
+	// for its namespace.
 	ToTypeScript() string
 
+	// ToTypeScriptWithOptions is like ToTypeScript, but formatted as per the given options.
+	ToTypeScriptWithOptions(opts RenderOptions) string
+
+	// declarationToTypeScript is like ToTypeScript, but without any namespace blocks, e.g. to be
+	// grouped with other type declarations in the same namespace (see Namespace), and formatted by
+	// the given formatter.
+	declarationToTypeScript(f *formatter) string
+
 	isTypeDeclaration()
 }
 
@@ -217,9 +491,17 @@ type TypeDeclaration interface {
 // TypeAliasDeclaration represents a TypeScript type alias declaration, e.g. type Color = string.
 type TypeAliasDeclaration struct {
 	// Namespace is the namespace that the type alias belongs to, or empty for the global namespace.
+	// Nested namespaces are separated by dots, e.g. "api.v1".
 	Namespace  string
 	Identifier string
 	Type       Type
+
+	// TypeParameters are the type parameters of a generic type alias, e.g. T in
+	// type List<T> = T[]. Optional.
+	TypeParameters []*TypeParameter
+
+	// Doc is the documentation of the type alias, rendered as a JSDoc comment. Optional.
+	Doc string
 }
 
 // TypeReference implements the TypeDeclaration interface.
@@ -236,10 +518,18 @@ func (a *TypeAliasDeclaration) QualifiedName() string {
 
 // ToTypeScript implements the TypeDeclaration interface.
 func (a *TypeAliasDeclaration) ToTypeScript() string {
-	if a.Namespace == "" {
-		return fmt.Sprintf("export type %s = %s;", a.Identifier, a.Type.ToTypeScript())
-	}
-	return fmt.Sprintf("export namespace %s { export type %s = %s; }", a.Namespace, a.Identifier, a.Type.ToTypeScript())
+	return a.ToTypeScriptWithOptions(RenderOptions{})
+}
+
+// ToTypeScriptWithOptions implements the TypeDeclaration interface.
+func (a *TypeAliasDeclaration) ToTypeScriptWithOptions(opts RenderOptions) string {
+	f := newFormatter(opts, qualifiedName)
+	return namespaced(a.Namespace, a.declarationToTypeScript(f), f)
+}
+
+// declarationToTypeScript implements the TypeDeclaration interface.
+func (a *TypeAliasDeclaration) declarationToTypeScript(f *formatter) string {
+	return fmt.Sprintf("%sexport type %s%s = %s%s", docComment(a.Doc, ""), a.Identifier, typeParameterList(a.TypeParameters), a.Type.toTypeScript(f), f.semicolon)
 }
 
 // isTypeDeclaration implements the TypeDeclaration interface.
@@ -256,23 +546,52 @@ type PropertySignature struct {
 	Identifier string
 	Type       Type
 	Optional   bool
+
+	// Readonly determines whether the property is rendered as readonly, e.g. readonly Name: string.
+	Readonly bool
+
+	// Doc is the documentation of the property, rendered as a JSDoc comment by
+	// InterfaceDeclaration.ToTypeScript(). Optional.
+	Doc string
 }
 
 // ToTypeScript converts the PropertySignature to a valid TypeScript interface property declaration.
 func (p *PropertySignature) ToTypeScript() string {
+	return p.toTypeScript(defaultFormatter())
+}
+
+// ToTypeScriptWithOptions is like ToTypeScript, but formatted as per the given options.
+func (p *PropertySignature) ToTypeScriptWithOptions(opts RenderOptions) string {
+	return p.toTypeScript(newFormatter(opts, qualifiedName))
+}
+
+// toTypeScript is like ToTypeScript, but formatted by the given formatter.
+func (p *PropertySignature) toTypeScript(f *formatter) string {
+	readonlyString := ""
+	if p.Readonly {
+		readonlyString = "readonly "
+	}
 	optionalString := ""
 	if p.Optional {
 		optionalString = "?"
 	}
-	return fmt.Sprintf("%s%s: %s;", p.Identifier, optionalString, p.Type.ToTypeScript())
+	return fmt.Sprintf("%s%s%s: %s%s", readonlyString, f.opts.PropertyName(p.Identifier), optionalString, p.Type.toTypeScript(f), f.semicolon)
 }
 
 // InterfaceDeclaration represents a TypeScript interface declaration.
 type InterfaceDeclaration struct {
 	// Namespace is the namespace that the interface belongs to, or empty for the global namespace.
+	// Nested namespaces are separated by dots, e.g. "api.v1".
 	Namespace  string
 	Identifier string
 	Properties []PropertySignature
+
+	// TypeParameters are the type parameters of a generic interface, e.g. T in
+	// interface Page<T> { ... }. Optional.
+	TypeParameters []*TypeParameter
+
+	// Doc is the documentation of the interface, rendered as a JSDoc comment. Optional.
+	Doc string
 }
 
 // TypeReference implements the TypeDeclaration interface.
@@ -289,34 +608,31 @@ func (i *InterfaceDeclaration) QualifiedName() string {
 
 // ToTypeScript implements the TypeDeclaration interface.
 func (i *InterfaceDeclaration) ToTypeScript() string {
-	var sb strings.Builder
+	return i.ToTypeScriptWithOptions(RenderOptions{})
+}
 
-	namespaced := i.Namespace != ""
-	interfaceIndentation := ""
-	propertyIndentation := "\t"
+// ToTypeScriptWithOptions implements the TypeDeclaration interface.
+func (i *InterfaceDeclaration) ToTypeScriptWithOptions(opts RenderOptions) string {
+	f := newFormatter(opts, qualifiedName)
+	return namespaced(i.Namespace, i.declarationToTypeScript(f), f)
+}
 
-	if namespaced {
-		sb.WriteString(fmt.Sprintf("export namespace %s {\n", i.Namespace))
-		interfaceIndentation = "\t"
-		propertyIndentation = "\t\t"
-	}
+// declarationToTypeScript implements the TypeDeclaration interface.
+func (i *InterfaceDeclaration) declarationToTypeScript(f *formatter) string {
+	var sb strings.Builder
 
-	sb.WriteString(interfaceIndentation)
-	sb.WriteString(fmt.Sprintf("export interface %s {\n", i.Identifier))
+	sb.WriteString(docComment(i.Doc, ""))
+	sb.WriteString(fmt.Sprintf("export interface %s%s {\n", i.Identifier, typeParameterList(i.TypeParameters)))
 
 	for _, prop := range i.Properties {
-		sb.WriteString(propertyIndentation)
-		sb.WriteString(prop.ToTypeScript())
+		sb.WriteString(docComment(prop.Doc, f.indent))
+		sb.WriteString(f.indent)
+		sb.WriteString(prop.toTypeScript(f))
 		sb.WriteString("\n")
 	}
 
-	sb.WriteString(interfaceIndentation)
 	sb.WriteString("}")
 
-	if namespaced {
-		sb.WriteString("\n}")
-	}
-
 	return sb.String()
 }
 
@@ -325,13 +641,205 @@ func (i *InterfaceDeclaration) isTypeDeclaration() {}
 
 var _ TypeDeclaration = (*InterfaceDeclaration)(nil)
 
+/////////////////////
+// EnumDeclaration //
+/////////////////////
+
+// EnumStyle determines how an EnumDeclaration is rendered.
+type EnumStyle int
+
+const (
+	// EnumKeyword renders an EnumDeclaration as a TypeScript enum, e.g.:
+	//
+	//	export enum Direction {
+	//		Up = 'up',
+	//		Down = 'down',
+	//	}
+	EnumKeyword EnumStyle = iota
+
+	// ConstObject renders an EnumDeclaration as a constant object with a type alias of the same name
+	// for the union of its values, e.g.:
+	//
+	//	export const Direction = {
+	//		Up: 'up',
+	//		Down: 'down',
+	//	} as const;
+	//	export type Direction = (typeof Direction)[keyof typeof Direction];
+	//
+	// Unlike TypeScript enums, the values of a constant object are assignable to the type alias, e.g.
+	// the literal 'up' is a valid Direction.
+	ConstObject
+)
+
+// EnumMember represents a member of a TypeScript enum declaration.
+type EnumMember struct {
+	Identifier string
+	Value      *LiteralType
+}
+
+// EnumDeclaration represents a TypeScript enum declaration, or its equivalent constant object
+// declaration. Unlike other type declarations, enum declarations declare both a type and a runtime
+// value.
+//
+// See https://www.typescriptlang.org/docs/handbook/enums.html.
+type EnumDeclaration struct {
+	// Namespace is the namespace that the enum belongs to, or empty for the global namespace.
+	// Nested namespaces are separated by dots, e.g. "api.v1".
+	Namespace  string
+	Identifier string
+	Members    []EnumMember
+	Style      EnumStyle
+
+	// Doc is the documentation of the enum, rendered as a JSDoc comment. Optional.
+	Doc string
+}
+
+// TypeReference implements the TypeDeclaration interface.
+func (e *EnumDeclaration) TypeReference() *TypeReference {
+	return &TypeReference{
+		typeDeclaration: e,
+	}
+}
+
+// QualifiedName implements the TypeDeclaration interface.
+func (e *EnumDeclaration) QualifiedName() string {
+	return makeQualifiedName(e.Namespace, e.Identifier)
+}
+
+// ToTypeScript implements the TypeDeclaration interface.
+//
+// It panics if the style is EnumKeyword and any members have boolean values, which TypeScript enums
+// do not support.
+func (e *EnumDeclaration) ToTypeScript() string {
+	return e.ToTypeScriptWithOptions(RenderOptions{})
+}
+
+// ToTypeScriptWithOptions implements the TypeDeclaration interface.
+func (e *EnumDeclaration) ToTypeScriptWithOptions(opts RenderOptions) string {
+	f := newFormatter(opts, qualifiedName)
+	return namespaced(e.Namespace, e.declarationToTypeScript(f), f)
+}
+
+// declarationToTypeScript implements the TypeDeclaration interface.
+func (e *EnumDeclaration) declarationToTypeScript(f *formatter) string {
+	var sb strings.Builder
+
+	sb.WriteString(docComment(e.Doc, ""))
+	if e.Style == ConstObject {
+		sb.WriteString(fmt.Sprintf("export const %s = {\n", e.Identifier))
+	} else {
+		sb.WriteString(fmt.Sprintf("export enum %s {\n", e.Identifier))
+	}
+
+	for _, member := range e.Members {
+		sb.WriteString(f.indent)
+		if e.Style == ConstObject {
+			sb.WriteString(fmt.Sprintf("%s: %s,\n", member.Identifier, member.Value.toTypeScript(f)))
+			continue
+		}
+		if member.Value.BasicType != String && member.Value.BasicType != Number {
+			panic(fmt.Sprintf("TypeScript enum member %q must be a string or a number, got: %q.", member.Identifier, member.Value.BasicType))
+		}
+		sb.WriteString(fmt.Sprintf("%s = %s,\n", member.Identifier, member.Value.toTypeScript(f)))
+	}
+
+	if e.Style == ConstObject {
+		sb.WriteString(fmt.Sprintf("} as const%s\n", f.semicolon))
+		sb.WriteString(fmt.Sprintf("export type %s = (typeof %s)[keyof typeof %s]%s", e.Identifier, e.Identifier, e.Identifier, f.semicolon))
+	} else {
+		sb.WriteString("}")
+	}
+
+	return sb.String()
+}
+
+// isTypeDeclaration implements the TypeDeclaration interface.
+func (e *EnumDeclaration) isTypeDeclaration() {}
+
+var _ TypeDeclaration = (*EnumDeclaration)(nil)
+
 ///////////////////////
 // Utility functions //
 ///////////////////////
 
+// TypeParameters returns the type parameters of the given type declaration, or nil if it isn't
+// generic.
+func TypeParameters(typeDeclaration TypeDeclaration) []*TypeParameter {
+	switch typeDeclaration := typeDeclaration.(type) {
+	case *InterfaceDeclaration:
+		return typeDeclaration.TypeParameters
+	case *TypeAliasDeclaration:
+		return typeDeclaration.TypeParameters
+	}
+	return nil
+}
+
+// docComment returns the given documentation as a JSDoc comment followed by a newline, with each
+// line prefixed by the given indentation, or the empty string if there is no documentation.
+//
+// Single-line documentation is rendered as a single-line JSDoc comment, e.g. "/** Hello. */".
+func docComment(doc, indentation string) string {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return ""
+	}
+
+	// Prevent the documentation from terminating the comment early.
+	doc = strings.ReplaceAll(doc, "*/", "*\\/")
+
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		return fmt.Sprintf("%s/** %s */\n", indentation, doc)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(indentation)
+	sb.WriteString("/**\n")
+	for _, line := range lines {
+		sb.WriteString(indentation)
+		sb.WriteString(strings.TrimRight(" * "+line, " "))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(indentation)
+	sb.WriteString(" */\n")
+	return sb.String()
+}
+
+// typeParameterList returns the given type parameters as a TypeScript type parameter list, e.g.
+// "<K, V>", or the empty string if there are none.
+func typeParameterList(typeParameters []*TypeParameter) string {
+	if len(typeParameters) == 0 {
+		return ""
+	}
+	identifiers := []string{}
+	for _, t := range typeParameters {
+		identifiers = append(identifiers, t.Identifier)
+	}
+	return fmt.Sprintf("<%s>", strings.Join(identifiers, ", "))
+}
+
+// isLiteralUnion returns true if the given type is a union type of one or more literal types.
+func isLiteralUnion(t Type) bool {
+	var types []Type
+	switch t := t.(type) {
+	case UnionType:
+		types = t.Types
+	case *UnionType:
+		types = t.Types
+	default:
+		return false
+	}
+	for _, t := range types {
+		if _, ok := t.(*LiteralType); !ok {
+			return false
+		}
+	}
+	return len(types) > 0
+}
+
 // makeQualifiedName returns a qualified TypeScript type name given a namespace and an identifier.
 // If the namespace is the empty string, the type is assumed to be declared in the global namespace.
-// Does not support nested namespaces.
+// Nested namespaces are separated by dots, e.g. "api.v1".
 func makeQualifiedName(namespace, identifier string) string {
 	if namespace != "" {
 		return fmt.Sprintf("%s.%s", namespace, identifier)
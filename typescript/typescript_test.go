@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBasicType_ToTypeScript_Success(t *testing.T) {
@@ -47,6 +48,22 @@ func TestArrayType_ToTypeScript_Sucess(t *testing.T) {
 	assert.Equal(t, "(string | number)[]", arrayType.ToTypeScript())
 }
 
+func TestTupleType_ToTypeScript_Success(t *testing.T) {
+	tupleType := TupleType{ElementTypes: []Type{Number, Number, Number}}
+	assert.Equal(t, "[number, number, number]", tupleType.ToTypeScript())
+
+	tupleType = TupleType{
+		ElementTypes: []Type{String, &UnionType{Types: []Type{Number, Null}}},
+		Readonly:     true,
+	}
+	assert.Equal(t, "readonly [string, number | null]", tupleType.ToTypeScript())
+
+	assert.Equal(t, "[]", (&TupleType{}).ToTypeScript())
+
+	arrayType := ArrayType{ItemsType: &tupleType}
+	assert.Equal(t, "(readonly [string, number | null])[]", arrayType.ToTypeScript())
+}
+
 func TestMapType_ToTypeScript_Success(t *testing.T) {
 	mapType := MapType{
 		IndexType: String,
@@ -65,6 +82,56 @@ func TestMapType_ToTypeScript_InvalidIndexType_Panics(t *testing.T) {
 	})
 }
 
+func TestMapType_ToTypeScript_ReferenceToTypeAliasIndexType_Success(t *testing.T) {
+	typeAliasDeclaration := TypeAliasDeclaration{
+		Identifier: "Mode",
+		Type:       String,
+	}
+	mapType := MapType{
+		IndexType: typeAliasDeclaration.TypeReference(),
+		ValueType: Number,
+	}
+	assert.False(t, mapType.IsMappedType())
+	assert.Equal(t, "{ [key: string]: number }", mapType.ToTypeScript())
+}
+
+func TestMapType_ToTypeScript_ReferenceToUnionIndexType_RendersMappedType(t *testing.T) {
+	typeAliasDeclaration := TypeAliasDeclaration{
+		Identifier: "Direction",
+		Type: &UnionType{
+			Types: []Type{
+				&LiteralType{BasicType: String, Literal: "up"},
+				&LiteralType{BasicType: String, Literal: "down"},
+			},
+		},
+	}
+	mapType := MapType{
+		IndexType: typeAliasDeclaration.TypeReference(),
+		ValueType: Number,
+	}
+	assert.True(t, mapType.IsMappedType())
+	assert.Equal(t, "{ [key in Direction]?: number }", mapType.ToTypeScript())
+}
+
+func TestMapType_ToTypeScript_ReferenceToEnumIndexType_RendersMappedType(t *testing.T) {
+	enumDeclaration := EnumDeclaration{
+		Identifier: "Direction",
+		Members: []EnumMember{
+			{Identifier: "Up", Value: &LiteralType{BasicType: String, Literal: "up"}},
+		},
+	}
+	typeAliasDeclaration := TypeAliasDeclaration{
+		Identifier: "Direction",
+		Type:       enumDeclaration.TypeReference(),
+	}
+	mapType := MapType{
+		IndexType: typeAliasDeclaration.TypeReference(),
+		ValueType: Number,
+	}
+	assert.True(t, mapType.IsMappedType())
+	assert.Equal(t, "{ [key in Direction]?: number }", mapType.ToTypeScript())
+}
+
 func TestUnionType_ToTypeScript_Success(t *testing.T) {
 	unionType := UnionType{
 		Types: []Type{
@@ -94,7 +161,25 @@ func TestTypeAliasDeclaration_ToTypeScript_Success(t *testing.T) {
 	assert.Equal(t, `export type Direction = 'up' | 'right' | 'down' | 'left';`, typeAliasDeclaration.ToTypeScript())
 
 	typeAliasDeclaration.Namespace = "Foo"
-	assert.Equal(t, `export namespace Foo { export type Direction = 'up' | 'right' | 'down' | 'left'; }`, typeAliasDeclaration.ToTypeScript())
+	assert.Equal(t, `export namespace Foo {
+	export type Direction = 'up' | 'right' | 'down' | 'left';
+}`, typeAliasDeclaration.ToTypeScript())
+}
+
+func TestTypeAliasDeclaration_ToTypeScript_WithDoc_Success(t *testing.T) {
+	typeAliasDeclaration := TypeAliasDeclaration{
+		Identifier: "Color",
+		Type:       String,
+		Doc:        "Color is a CSS color.",
+	}
+	assert.Equal(t, `/** Color is a CSS color. */
+export type Color = string;`, typeAliasDeclaration.ToTypeScript())
+
+	typeAliasDeclaration.Namespace = "Foo"
+	assert.Equal(t, `export namespace Foo {
+	/** Color is a CSS color. */
+	export type Color = string;
+}`, typeAliasDeclaration.ToTypeScript())
 }
 
 func TestTypeAliasDeclaration_TypeReference_ReferenceReflectsChangesInDeclaration(t *testing.T) {
@@ -113,6 +198,41 @@ func TestTypeAliasDeclaration_TypeReference_ReferenceReflectsChangesInDeclaratio
 	assert.Equal(t, "Foo.AnotherAlias", typeReference.ToTypeScript())
 }
 
+func TestReadonlyTypes_ToTypeScript_Success(t *testing.T) {
+	arrayType := ArrayType{ItemsType: String, Readonly: true}
+	assert.Equal(t, "readonly string[]", arrayType.ToTypeScript())
+
+	arrayType = ArrayType{ItemsType: &ArrayType{ItemsType: Number, Readonly: true}}
+	assert.Equal(t, "(readonly number[])[]", arrayType.ToTypeScript())
+
+	mapType := MapType{IndexType: String, ValueType: Number, Readonly: true}
+	assert.Equal(t, "Readonly<Record<string, number>>", mapType.ToTypeScript())
+
+	direction := &TypeAliasDeclaration{
+		Identifier: "Direction",
+		Type: &UnionType{
+			Types: []Type{
+				&LiteralType{BasicType: String, Literal: "up"},
+				&LiteralType{BasicType: String, Literal: "down"},
+			},
+		},
+	}
+	mapType = MapType{IndexType: direction.TypeReference(), ValueType: Number, Readonly: true}
+	assert.Equal(t, "Readonly<Partial<Record<Direction, number>>>", mapType.ToTypeScript())
+
+	interfaceDeclaration := InterfaceDeclaration{
+		Identifier: "Person",
+		Properties: []PropertySignature{
+			{Identifier: "Name", Type: String, Readonly: true},
+			{Identifier: "Tags", Type: &ArrayType{ItemsType: String, Readonly: true}, Optional: true, Readonly: true},
+		},
+	}
+	assert.Equal(t, `export interface Person {
+	readonly Name: string;
+	readonly Tags?: readonly string[];
+}`, interfaceDeclaration.ToTypeScript())
+}
+
 func TestInterfaceDeclaration_ToTypeScript_Success(t *testing.T) {
 	interfaceDeclaration := InterfaceDeclaration{
 		Identifier: "Person",
@@ -143,6 +263,49 @@ func TestInterfaceDeclaration_ToTypeScript_Success(t *testing.T) {
 }`, interfaceDeclaration.ToTypeScript())
 }
 
+func TestInterfaceDeclaration_ToTypeScript_WithDocs_Success(t *testing.T) {
+	interfaceDeclaration := InterfaceDeclaration{
+		Identifier: "Person",
+		Doc:        "Person is a human being.\n\nPeople are */ nice.",
+		Properties: []PropertySignature{
+			{
+				Identifier: "Name",
+				Type:       String,
+				Doc:        "Name is the full name.",
+			},
+			{
+				Identifier: "Age",
+				Type:       Number,
+			},
+		},
+	}
+
+	assert.Equal(t, `/**
+ * Person is a human being.
+ *
+ * People are *\/ nice.
+ */
+export interface Person {
+	/** Name is the full name. */
+	Name: string;
+	Age: number;
+}`, interfaceDeclaration.ToTypeScript())
+
+	interfaceDeclaration.Namespace = "Foo"
+	assert.Equal(t, `export namespace Foo {
+	/**
+	 * Person is a human being.
+	 *
+	 * People are *\/ nice.
+	 */
+	export interface Person {
+		/** Name is the full name. */
+		Name: string;
+		Age: number;
+	}
+}`, interfaceDeclaration.ToTypeScript())
+}
+
 func TestInterfaceDeclaration_TypeReference_ReferenceReflectsChangesInDeclaration(t *testing.T) {
 	interfaceDeclaration := InterfaceDeclaration{
 		Identifier: "MyInterface",
@@ -157,3 +320,655 @@ func TestInterfaceDeclaration_TypeReference_ReferenceReflectsChangesInDeclaratio
 	interfaceDeclaration.Identifier = "AnotherInterface"
 	assert.Equal(t, "Foo.AnotherInterface", typeReference.ToTypeScript())
 }
+
+func TestEnumDeclaration_ToTypeScript_Success(t *testing.T) {
+	enumDeclaration := EnumDeclaration{
+		Identifier: "Direction",
+		Members: []EnumMember{
+			{Identifier: "Up", Value: &LiteralType{BasicType: String, Literal: "up"}},
+			{Identifier: "Down", Value: &LiteralType{BasicType: String, Literal: "down"}},
+		},
+		Doc: "Direction is a direction.",
+	}
+
+	assert.Equal(t, `/** Direction is a direction. */
+export enum Direction {
+	Up = 'up',
+	Down = 'down',
+}`, enumDeclaration.ToTypeScript())
+
+	enumDeclaration.Namespace = "Foo"
+	assert.Equal(t, `export namespace Foo {
+	/** Direction is a direction. */
+	export enum Direction {
+		Up = 'up',
+		Down = 'down',
+	}
+}`, enumDeclaration.ToTypeScript())
+}
+
+func TestEnumDeclaration_ToTypeScript_ConstObject_Success(t *testing.T) {
+	enumDeclaration := EnumDeclaration{
+		Identifier: "Answer",
+		Members: []EnumMember{
+			{Identifier: "Yes", Value: &LiteralType{BasicType: Boolean, Literal: "true"}},
+			{Identifier: "No", Value: &LiteralType{BasicType: Boolean, Literal: "false"}},
+		},
+		Style: ConstObject,
+	}
+
+	assert.Equal(t, `export const Answer = {
+	Yes: true,
+	No: false,
+} as const;
+export type Answer = (typeof Answer)[keyof typeof Answer];`, enumDeclaration.ToTypeScript())
+
+	enumDeclaration.Namespace = "Foo"
+	assert.Equal(t, `export namespace Foo {
+	export const Answer = {
+		Yes: true,
+		No: false,
+	} as const;
+	export type Answer = (typeof Answer)[keyof typeof Answer];
+}`, enumDeclaration.ToTypeScript())
+}
+
+func TestEnumDeclaration_ToTypeScript_BooleanMember_Panics(t *testing.T) {
+	enumDeclaration := EnumDeclaration{
+		Identifier: "Answer",
+		Members: []EnumMember{
+			{Identifier: "Yes", Value: &LiteralType{BasicType: Boolean, Literal: "true"}},
+		},
+	}
+
+	assert.PanicsWithValue(t, `TypeScript enum member "Yes" must be a string or a number, got: "boolean".`, func() {
+		enumDeclaration.ToTypeScript()
+	})
+}
+
+func TestEnumDeclaration_TypeReference_ReferenceReflectsChangesInDeclaration(t *testing.T) {
+	enumDeclaration := EnumDeclaration{
+		Identifier: "MyEnum",
+	}
+
+	typeReference := enumDeclaration.TypeReference()
+	assert.Equal(t, "MyEnum", typeReference.ToTypeScript())
+
+	enumDeclaration.Namespace = "Foo"
+	assert.Equal(t, "Foo.MyEnum", typeReference.ToTypeScript())
+}
+
+func TestTypeGuard_InterfaceDeclaration_Success(t *testing.T) {
+	position := &InterfaceDeclaration{
+		Namespace:  "Foo",
+		Identifier: "Position",
+	}
+	direction := &TypeAliasDeclaration{
+		Identifier: "Direction",
+		Type:       String,
+	}
+	turtle := &InterfaceDeclaration{
+		Identifier: "Turtle",
+		Properties: []PropertySignature{
+			{Identifier: "Position", Type: position.TypeReference()},
+			{Identifier: "Direction", Type: direction.TypeReference()},
+			{Identifier: "Speed", Type: &LiteralType{BasicType: Number, Literal: "1"}},
+			{Identifier: "Tags", Type: &UnionType{Types: []Type{&ArrayType{ItemsType: String}, Null}}, Optional: true},
+			{Identifier: "Parent"},
+			{Identifier: "Stats", Type: &MapType{IndexType: String, ValueType: &ArrayType{ItemsType: Number}}},
+			{Identifier: "Extra", Type: Any},
+		},
+	}
+	// The Parent property is recursive.
+	turtle.Properties[4].Type = UnionType{Types: []Type{turtle.TypeReference(), Null}}
+
+	assert.Equal(t, `export function isTurtle(x: unknown): x is Turtle {
+	if (typeof x !== 'object' || x === null || Array.isArray(x)) {
+		return false;
+	}
+	const o = x as Record<string, unknown>;
+	return (
+		Foo.isPosition(o['Position']) &&
+		isDirection(o['Direction']) &&
+		o['Speed'] === 1 &&
+		(o['Tags'] === undefined || (Array.isArray(o['Tags']) && o['Tags'].every(($v1) => typeof $v1 === 'string')) || o['Tags'] === null) &&
+		(isTurtle(o['Parent']) || o['Parent'] === null) &&
+		(typeof o['Stats'] === 'object' && o['Stats'] !== null && !Array.isArray(o['Stats']) && Object.values(o['Stats']).every(($v1) => (Array.isArray($v1) && $v1.every(($v2) => typeof $v2 === 'number'))))
+	);
+}`, TypeGuard(turtle))
+
+	assert.Equal(t, `export namespace Foo {
+	export function isPosition(x: unknown): x is Foo.Position {
+		if (typeof x !== 'object' || x === null || Array.isArray(x)) {
+			return false;
+		}
+		return true;
+	}
+}`, TypeGuard(position))
+	assert.Equal(t, "Foo.isPosition", TypeGuardName(position))
+}
+
+func TestTypeGuard_TypeAliasAndEnumDeclarations_Success(t *testing.T) {
+	direction := &TypeAliasDeclaration{
+		Identifier: "Direction",
+		Type: &UnionType{
+			Types: []Type{
+				&LiteralType{BasicType: String, Literal: "up"},
+				&LiteralType{BasicType: String, Literal: "down"},
+			},
+		},
+	}
+	assert.Equal(t, `export function isDirection(x: unknown): x is Direction {
+	return x === 'up' || x === 'down';
+}`, TypeGuard(direction))
+
+	anything := &TypeAliasDeclaration{
+		Identifier: "Anything",
+		Type:       &UnionType{Types: []Type{Any, Null}},
+	}
+	assert.Equal(t, `export function isAnything(x: unknown): x is Anything {
+	return true;
+}`, TypeGuard(anything))
+
+	speed := &EnumDeclaration{
+		Namespace:  "Foo",
+		Identifier: "Speed",
+		Members: []EnumMember{
+			{Identifier: "Slow", Value: &LiteralType{BasicType: Number, Literal: "1"}},
+			{Identifier: "Fast", Value: &LiteralType{BasicType: Number, Literal: "2"}},
+		},
+	}
+	assert.Equal(t, `export namespace Foo {
+	export function isSpeed(x: unknown): x is Foo.Speed {
+		return x === 1 || x === 2;
+	}
+}`, TypeGuard(speed))
+}
+
+func TestTypeGuardName_IdentifiersDifferingInCase_DistinctNames(t *testing.T) {
+	lower := &TypeAliasDeclaration{Namespace: "Foo", Identifier: "speed", Type: Number}
+	upper := &TypeAliasDeclaration{Namespace: "Foo", Identifier: "Speed", Type: String}
+	assert.Equal(t, "Foo.is_speed", TypeGuardName(lower))
+	assert.Equal(t, "Foo.isSpeed", TypeGuardName(upper))
+	assert.Equal(t, `export namespace Foo {
+	export function is_speed(x: unknown): x is Foo.speed {
+		return typeof x === 'number';
+	}
+}`, TypeGuard(lower))
+}
+
+func TestTypeGuard_TupleType_ChecksLengthAndElements(t *testing.T) {
+	segment := &TypeAliasDeclaration{
+		Identifier: "Segment",
+		Type: &TupleType{
+			ElementTypes: []Type{
+				&ArrayType{ItemsType: Number},
+				&UnionType{Types: []Type{String, Null}},
+				Any,
+			},
+		},
+	}
+	assert.Equal(t, `export function isSegment(x: unknown): x is Segment {
+	return (Array.isArray(x) && x.length === 3 && (Array.isArray(x[0]) && x[0].every(($v1) => typeof $v1 === 'number')) && (typeof x[1] === 'string' || x[1] === null));
+}`, TypeGuard(segment))
+}
+
+func TestGenericDeclarations_ToTypeScript_Success(t *testing.T) {
+	item := &InterfaceDeclaration{
+		Identifier: "Item",
+	}
+	k := &TypeParameter{Identifier: "K"}
+	v := &TypeParameter{Identifier: "V"}
+	pair := &InterfaceDeclaration{
+		Namespace:      "Foo",
+		Identifier:     "Pair",
+		TypeParameters: []*TypeParameter{k, v},
+		Properties: []PropertySignature{
+			{Identifier: "Key", Type: k},
+			{Identifier: "Value", Type: v},
+		},
+	}
+	list := &TypeAliasDeclaration{
+		Identifier:     "List",
+		TypeParameters: []*TypeParameter{v},
+		Type:           &ArrayType{ItemsType: v},
+	}
+
+	assert.Equal(t, `export namespace Foo {
+	export interface Pair<K, V> {
+		Key: K;
+		Value: V;
+	}
+}`, pair.ToTypeScript())
+	assert.Equal(t, "export type List<V> = V[];", list.ToTypeScript())
+
+	reference := &GenericTypeReference{
+		TypeReference: pair.TypeReference(),
+		TypeArguments: []Type{
+			String,
+			&GenericTypeReference{TypeReference: list.TypeReference(), TypeArguments: []Type{item.TypeReference()}},
+		},
+	}
+	assert.Equal(t, "Foo.Pair<string, List<Item>>", reference.ToTypeScript())
+	assert.Equal(t, []*TypeParameter{k, v}, TypeParameters(pair))
+	assert.Nil(t, TypeParameters(item))
+}
+
+func TestTypeGuard_GenericDeclarations_Success(t *testing.T) {
+	item := &InterfaceDeclaration{
+		Identifier: "Item",
+	}
+	v := &TypeParameter{Identifier: "V"}
+	list := &TypeAliasDeclaration{
+		Identifier:     "List",
+		TypeParameters: []*TypeParameter{v},
+		Type:           &ArrayType{ItemsType: v},
+	}
+	catalog := &InterfaceDeclaration{
+		Identifier: "Catalog",
+		Properties: []PropertySignature{
+			{Identifier: "Items", Type: &GenericTypeReference{TypeReference: list.TypeReference(), TypeArguments: []Type{item.TypeReference()}}},
+			{Identifier: "Names", Type: &GenericTypeReference{TypeReference: list.TypeReference(), TypeArguments: []Type{String}}},
+		},
+	}
+
+	assert.Equal(t, `export function isList<V>(x: unknown, isV: (x: unknown) => x is V): x is List<V> {
+	return (Array.isArray(x) && x.every(($v1) => isV($v1)));
+}`, TypeGuard(list))
+	assert.Equal(t, `export function isCatalog(x: unknown): x is Catalog {
+	if (typeof x !== 'object' || x === null || Array.isArray(x)) {
+		return false;
+	}
+	const o = x as Record<string, unknown>;
+	return (
+		isList(o['Items'], isItem) &&
+		isList(o['Names'], ($v1: unknown): $v1 is string => typeof $v1 === 'string')
+	);
+}`, TypeGuard(catalog))
+}
+
+func TestInterfaceDeclaration_ToTypeScript_NestedNamespace_Success(t *testing.T) {
+	interfaceDeclaration := InterfaceDeclaration{
+		Namespace:  "api.v1",
+		Identifier: "Job",
+		Properties: []PropertySignature{
+			{
+				Identifier: "Name",
+				Type:       String,
+			},
+		},
+	}
+
+	assert.Equal(t, "api.v1.Job", interfaceDeclaration.QualifiedName())
+	assert.Equal(t, `export namespace api {
+	export namespace v1 {
+		export interface Job {
+			Name: string;
+		}
+	}
+}`, interfaceDeclaration.ToTypeScript())
+}
+
+func TestNamespace_ToTypeScript_GroupsDeclarationsByNamespace(t *testing.T) {
+	job := &InterfaceDeclaration{
+		Namespace:  "api.v1",
+		Identifier: "Job",
+	}
+	user := &InterfaceDeclaration{
+		Namespace:  "api",
+		Identifier: "User",
+	}
+	jobID := &TypeAliasDeclaration{
+		Namespace:  "api.v1",
+		Identifier: "JobID",
+		Type:       String,
+	}
+	status := &TypeAliasDeclaration{
+		Identifier: "Status",
+		Type:       String,
+	}
+	v2Job := &InterfaceDeclaration{
+		Namespace:  "api.v2",
+		Identifier: "Job",
+		Properties: []PropertySignature{
+			{Identifier: "ID", Type: jobID.TypeReference()},
+		},
+	}
+
+	var global Namespace
+	for _, typeDeclaration := range []TypeDeclaration{job, user, jobID, status, v2Job} {
+		global.AddTypeDeclaration(typeDeclaration)
+	}
+	global.AddTypeGuard(jobID)
+
+	assert.Equal(t, `export namespace api {
+	export namespace v1 {
+		export interface Job {
+		}
+
+		export type JobID = string;
+
+		export function isJobID(x: unknown): x is api.v1.JobID {
+			return typeof x === 'string';
+		}
+	}
+
+	export interface User {
+	}
+
+	export namespace v2 {
+		export interface Job {
+			ID: api.v1.JobID;
+		}
+	}
+}
+
+export type Status = string;`, global.ToTypeScript())
+	assert.Len(t, global.Statements(), 2)
+}
+
+func TestModules_ToTypeScript_ImportsReferencedTypeDeclarations(t *testing.T) {
+	jobID := &TypeAliasDeclaration{
+		Namespace:  "v1",
+		Identifier: "JobID",
+		Type:       String,
+	}
+	job := &InterfaceDeclaration{
+		Namespace:  "v1",
+		Identifier: "Job",
+		Properties: []PropertySignature{
+			{Identifier: "ID", Type: jobID.TypeReference()},
+		},
+	}
+	otherJob := &InterfaceDeclaration{
+		Namespace:  "v2",
+		Identifier: "Job",
+	}
+	user := &InterfaceDeclaration{
+		Identifier: "User",
+		Properties: []PropertySignature{
+			{Identifier: "Jobs", Type: &ArrayType{ItemsType: job.TypeReference()}},
+			{Identifier: "OtherJobs", Type: &MapType{IndexType: jobID.TypeReference(), ValueType: otherJob.TypeReference()}},
+		},
+	}
+	modulePaths := map[TypeDeclaration]string{
+		jobID:    "api/v1/jobs",
+		job:      "api/v1/jobs",
+		otherJob: "api/v2/jobs",
+		user:     "users",
+	}
+
+	modules := Modules([]TypeDeclaration{user, jobID, job, otherJob}, func(typeDeclaration TypeDeclaration) string {
+		return modulePaths[typeDeclaration]
+	})
+	require.Len(t, modules, 3)
+	assert.Equal(t, "api/v1/jobs", modules[0].Path)
+	assert.Equal(t, []TypeDeclaration{jobID, job}, modules[0].TypeDeclarations)
+	assert.Equal(t, "api/v2/jobs", modules[1].Path)
+	assert.Equal(t, "users", modules[2].Path)
+
+	assert.Equal(t, `export type JobID = string;
+
+export interface Job {
+	ID: JobID;
+}`, modules[0].ToTypeScript())
+
+	modules[2].TypeGuards = true
+	assert.Equal(t, `import type { Job } from './api/v1/jobs';
+import { isJob } from './api/v1/jobs';
+import type { Job as Job2 } from './api/v2/jobs';
+import { isJob as isJob2 } from './api/v2/jobs';
+
+export interface User {
+	Jobs: Job[];
+	OtherJobs: { [key: string]: Job2 };
+}
+
+export function isUser(x: unknown): x is User {
+	if (typeof x !== 'object' || x === null || Array.isArray(x)) {
+		return false;
+	}
+	const o = x as Record<string, unknown>;
+	return (
+		(Array.isArray(o['Jobs']) && o['Jobs'].every(($v1) => isJob($v1))) &&
+		(typeof o['OtherJobs'] === 'object' && o['OtherJobs'] !== null && !Array.isArray(o['OtherJobs']) && Object.values(o['OtherJobs']).every(($v1) => isJob2($v1)))
+	);
+}`, modules[2].ToTypeScript())
+}
+
+func TestModules_ModulesImportEachOther_ImportsRelativeToModulePaths(t *testing.T) {
+	parent := &InterfaceDeclaration{
+		Identifier: "Parent",
+	}
+	child := &InterfaceDeclaration{
+		Identifier: "Child",
+		Properties: []PropertySignature{
+			{Identifier: "Parent", Type: parent.TypeReference()},
+		},
+	}
+	parent.Properties = []PropertySignature{
+		{Identifier: "Children", Type: &ArrayType{ItemsType: child.TypeReference()}},
+	}
+
+	modules := Modules([]TypeDeclaration{child, parent}, func(typeDeclaration TypeDeclaration) string {
+		if typeDeclaration == parent {
+			return "family/parents"
+		}
+		return "family/children/child"
+	})
+	require.Len(t, modules, 2)
+	assert.Equal(t, "family/parents", modules[0].Path)
+	assert.Equal(t, "family/children/child", modules[1].Path)
+	assert.Equal(t, `import type { Child } from './children/child';
+
+export interface Parent {
+	Children: Child[];
+}`, modules[0].ToTypeScript())
+	assert.Equal(t, `import type { Parent } from '../parents';
+
+export interface Child {
+	Parent: Parent;
+}`, modules[1].ToTypeScript())
+}
+
+func TestToTypeScriptWithOptions_FormattedAsPerOptions(t *testing.T) {
+	opts := RenderOptions{
+		Indent:         "    ",
+		Quotes:         DoubleQuotes,
+		OmitSemicolons: true,
+	}
+
+	direction := &EnumDeclaration{
+		Namespace:  "compass",
+		Identifier: "Direction",
+		Style:      ConstObject,
+		Members: []EnumMember{
+			{Identifier: "Up", Value: &LiteralType{BasicType: String, Literal: "up"}},
+			{Identifier: "Down", Value: &LiteralType{BasicType: String, Literal: "down"}},
+		},
+	}
+	assert.Equal(t, `export namespace compass {
+    export const Direction = {
+        Up: "up",
+        Down: "down",
+    } as const
+    export type Direction = (typeof Direction)[keyof typeof Direction]
+}`, direction.ToTypeScriptWithOptions(opts))
+
+	turtle := &InterfaceDeclaration{
+		Identifier: "Turtle",
+		Properties: []PropertySignature{
+			{Identifier: "Direction", Type: direction.TypeReference(), Doc: "Direction is where the turtle is heading."},
+		},
+	}
+	assert.Equal(t, `export interface Turtle {
+    /** Direction is where the turtle is heading. */
+    Direction: compass.Direction
+}`, turtle.ToTypeScriptWithOptions(opts))
+
+	modules := Modules([]TypeDeclaration{direction, turtle}, func(typeDeclaration TypeDeclaration) string {
+		if typeDeclaration == direction {
+			return "compass"
+		}
+		return "turtles"
+	})
+	require.Len(t, modules, 2)
+	assert.Equal(t, `import type { Direction } from "./compass"
+
+export interface Turtle {
+    /** Direction is where the turtle is heading. */
+    Direction: Direction
+}`, modules[1].ToTypeScriptWithOptions(opts))
+
+	// The zero RenderOptions match ToTypeScript().
+	assert.Equal(t, turtle.ToTypeScript(), turtle.ToTypeScriptWithOptions(RenderOptions{}))
+	assert.Equal(t, TypeGuard(turtle), TypeGuardWithOptions(turtle, RenderOptions{}))
+}
+
+func TestRenderOptions_Quote_Escaped(t *testing.T) {
+	assert.Equal(t, `'zod'`, RenderOptions{}.Quote("zod"))
+	assert.Equal(t, `'say "hi"'`, RenderOptions{}.Quote(`say "hi"`))
+	assert.Equal(t, `'it\'s \\ \r\n'`, RenderOptions{Quotes: SingleQuotes}.Quote("it's \\ \r\n"))
+	assert.Equal(t, `"it's"`, RenderOptions{Quotes: DoubleQuotes}.Quote("it's"))
+	assert.Equal(t, `"say \"hi\" \\ \r\n"`, RenderOptions{Quotes: DoubleQuotes}.Quote("say \"hi\" \\ \r\n"))
+	assert.Equal(t, `"\u2028\u2029"`, RenderOptions{Quotes: DoubleQuotes}.Quote("\u2028\u2029"))
+}
+
+func TestRenderOptions_PropertyName_QuotedIfNotIdentifier(t *testing.T) {
+	assert.Equal(t, "Name", RenderOptions{}.PropertyName("Name"))
+	assert.Equal(t, "$_name2", RenderOptions{}.PropertyName("$_name2"))
+	assert.Equal(t, "'-'", RenderOptions{}.PropertyName("-"))
+	assert.Equal(t, "'2fa'", RenderOptions{}.PropertyName("2fa"))
+	assert.Equal(t, `"first-name"`, RenderOptions{Quotes: DoubleQuotes}.PropertyName("first-name"))
+}
+
+func TestRenderOptions_File_HeaderAndLineEndings(t *testing.T) {
+	assert.Equal(t, "// DO NOT EDIT. This file is automatically generated.\n\nexport type Name = string;\n", RenderOptions{}.File("export type Name = string;"))
+	assert.Equal(t, "// DO NOT EDIT. This file is automatically generated.\n", RenderOptions{}.File(""))
+
+	opts := RenderOptions{
+		Header:     "/*\n * Copyright 2024 Example Inc.\n */\n",
+		LineEnding: "\r\n",
+	}
+	assert.Equal(t, "/*\r\n * Copyright 2024 Example Inc.\r\n */\r\n\r\nexport interface Empty {\r\n}\r\n", opts.File("export interface Empty {\n}"))
+}
+
+func TestMarshalTypeDeclarations_RoundTrip_RendersIdenticalTypeScript(t *testing.T) {
+	itemsType := &TypeParameter{Identifier: "T"}
+	page := &InterfaceDeclaration{
+		Namespace:      "api",
+		Identifier:     "Page",
+		TypeParameters: []*TypeParameter{itemsType},
+		Doc:            "Page is a page of results.",
+	}
+	page.Properties = []PropertySignature{
+		{Identifier: "Items", Type: &UnionType{Types: []Type{&ArrayType{ItemsType: itemsType}, Null}}},
+		{Identifier: "Next", Type: &GenericTypeReference{TypeReference: page.TypeReference(), TypeArguments: []Type{itemsType}}, Optional: true},
+	}
+	direction := &EnumDeclaration{
+		Namespace:  "api",
+		Identifier: "Direction",
+		Style:      ConstObject,
+		Members: []EnumMember{
+			{Identifier: "Up", Value: &LiteralType{BasicType: String, Literal: "up"}},
+			{Identifier: "Empty", Value: &LiteralType{BasicType: String, Literal: ""}},
+		},
+	}
+	turtle := &InterfaceDeclaration{
+		Identifier: "Turtle",
+		Properties: []PropertySignature{
+			{Identifier: "Directions", Type: &MapType{IndexType: direction.TypeReference(), ValueType: Boolean}, Doc: "Directions it may go in."},
+			{Identifier: "Pages", Type: &GenericTypeReference{TypeReference: page.TypeReference(), TypeArguments: []Type{Any}}},
+			{Identifier: "Position", Type: &TupleType{ElementTypes: []Type{Number, Number}, Readonly: true}},
+		},
+	}
+	// References to type declarations declared later are resolved too.
+	turtles := &TypeAliasDeclaration{
+		Identifier: "Turtles",
+		Type:       &ArrayType{ItemsType: turtle.TypeReference()},
+	}
+	typeDeclarations := []TypeDeclaration{turtles, page, direction, turtle}
+
+	data, err := MarshalTypeDeclarations(typeDeclarations)
+	require.NoError(t, err)
+	loaded, err := UnmarshalTypeDeclarations(data)
+	require.NoError(t, err)
+	require.Len(t, loaded, len(typeDeclarations))
+	for i, typeDeclaration := range typeDeclarations {
+		assert.Equal(t, typeDeclaration.ToTypeScript(), loaded[i].ToTypeScript())
+		assert.Equal(t, TypeGuard(typeDeclaration), TypeGuard(loaded[i]))
+	}
+
+	// References are resolved to the loaded type declarations.
+	assert.Same(t, loaded[3], loaded[0].(*TypeAliasDeclaration).Type.(*ArrayType).ItemsType.(*TypeReference).TypeDeclaration())
+
+	// The encoding is stable.
+	reencoded, err := MarshalTypeDeclarations(loaded)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(reencoded))
+}
+
+func TestMarshalTypeDeclarations_Encoding(t *testing.T) {
+	name := &TypeAliasDeclaration{Namespace: "api", Identifier: "Name", Type: String}
+	user := &InterfaceDeclaration{
+		Identifier: "User",
+		Properties: []PropertySignature{
+			{Identifier: "Name", Type: name.TypeReference(), Optional: true},
+		},
+	}
+	data, err := MarshalTypeDeclarations([]TypeDeclaration{name, user})
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "version": 1,
+  "typeDeclarations": [
+    {
+      "kind": "typeAlias",
+      "namespace": "api",
+      "identifier": "Name",
+      "type": {
+        "kind": "basic",
+        "basicType": "string"
+      }
+    },
+    {
+      "kind": "interface",
+      "identifier": "User",
+      "properties": [
+        {
+          "identifier": "Name",
+          "type": {
+            "kind": "reference",
+            "name": "api.Name"
+          },
+          "optional": true
+        }
+      ]
+    }
+  ]
+}`, string(data))
+}
+
+func TestUnmarshalTypeDeclarations_Invalid_Error(t *testing.T) {
+	test := func(name, data, expectedErr string) {
+		t.Run(name, func(t *testing.T) {
+			_, err := UnmarshalTypeDeclarations([]byte(data))
+			require.EqualError(t, err, expectedErr)
+		})
+	}
+
+	test("UnsupportedVersion", `{"version": 2, "typeDeclarations": []}`, "unsupported JSON encoding version 2")
+	test("UnknownDeclarationKind", `{"version": 1, "typeDeclarations": [{"kind": "class", "identifier": "Turtle"}]}`, `unknown kind of TypeScript type declaration "class"`)
+	test("DuplicateDeclaration", `{"version": 1, "typeDeclarations": [{"kind": "interface", "identifier": "Turtle"}, {"kind": "enum", "identifier": "Turtle"}]}`, `TypeScript type "Turtle" is declared more than once`)
+	test("UndeclaredReference", `{"version": 1, "typeDeclarations": [{"kind": "typeAlias", "identifier": "Turtles", "type": {"kind": "array", "items": {"kind": "reference", "name": "Turtle"}}}]}`, `Turtles: TypeScript type "Turtle" is not declared`)
+	test("UnknownBasicType", `{"version": 1, "typeDeclarations": [{"kind": "interface", "identifier": "Turtle", "properties": [{"identifier": "Age", "type": {"kind": "basic", "basicType": "bigint"}}]}]}`, `property Age of Turtle: unknown basic type "bigint"`)
+	test("MissingType", `{"version": 1, "typeDeclarations": [{"kind": "typeAlias", "identifier": "Name"}]}`, "Name: missing type")
+	test("InvalidBooleanLiteral", `{"version": 1, "typeDeclarations": [{"kind": "typeAlias", "identifier": "Yes", "type": {"kind": "literal", "basicType": "boolean", "literal": "yes"}}]}`, `Yes: invalid boolean literal "yes"`)
+	test("InvalidNumberLiteral", `{"version": 1, "typeDeclarations": [{"kind": "enum", "identifier": "Level", "members": [{"identifier": "High", "value": {"kind": "literal", "basicType": "number", "literal": "high"}}]}]}`, `member High of Level: invalid number literal "high"`)
+	test("NullLiteral", `{"version": 1, "typeDeclarations": [{"kind": "typeAlias", "identifier": "Nothing", "type": {"kind": "literal", "basicType": "null", "literal": "null"}}]}`, `Nothing: unknown basic type "null" of literal "null"`)
+	test("UnknownLiteralBasicType", `{"version": 1, "typeDeclarations": [{"kind": "typeAlias", "identifier": "Foo", "type": {"kind": "literal", "basicType": "foo", "literal": "bar"}}]}`, `Foo: unknown basic type "foo" of literal "bar"`)
+	test("BooleanIndexType", `{"version": 1, "typeDeclarations": [{"kind": "typeAlias", "identifier": "Flags", "type": {"kind": "map", "index": {"kind": "basic", "basicType": "boolean"}, "value": {"kind": "basic", "basicType": "string"}}}]}`, `Flags: TypeScript type "boolean" cannot be used as an index signature parameter type`)
+	test("BooleanLiteralIndexType", `{"version": 1, "typeDeclarations": [{"kind": "typeAlias", "identifier": "Flags", "type": {"kind": "map", "index": {"kind": "literal", "basicType": "boolean", "literal": "true"}, "value": {"kind": "basic", "basicType": "string"}}}]}`, `Flags: TypeScript type "true" cannot be used as an index signature parameter type`)
+	test("NumberLiteralIndexType", `{"version": 1, "typeDeclarations": [{"kind": "interface", "identifier": "Turtle", "properties": [{"identifier": "Scores", "type": {"kind": "array", "items": {"kind": "map", "index": {"kind": "literal", "basicType": "number", "literal": "1"}, "value": {"kind": "basic", "basicType": "number"}}}}]}]}`, `property Scores of Turtle: TypeScript type "1" cannot be used as an index signature parameter type`)
+	test("InterfaceReferenceIndexType", `{"version": 1, "typeDeclarations": [{"kind": "typeAlias", "identifier": "Pets", "type": {"kind": "map", "index": {"kind": "reference", "name": "Key"}, "value": {"kind": "basic", "basicType": "string"}}}, {"kind": "typeAlias", "identifier": "Key", "type": {"kind": "reference", "name": "Turtle"}}, {"kind": "interface", "identifier": "Turtle"}]}`, `Pets: TypeScript type "Turtle" cannot be used as an index signature parameter type`)
+	test("BooleanEnumMember", `{"version": 1, "typeDeclarations": [{"kind": "enum", "identifier": "Answer", "members": [{"identifier": "Yes", "value": {"kind": "literal", "basicType": "boolean", "literal": "true"}}]}]}`, `member Yes of Answer: value must be a string or a number, got "boolean"`)
+}
@@ -0,0 +1,329 @@
+// Package zod renders TypeScript type declarations as zod schemas.
+//
+// Zod (https://zod.dev) is a TypeScript library for parsing and validating data at runtime. Given
+// the type declarations produced by Go2TS (see go2ts.Go2TS.TypeDeclarations()), this package writes
+// one schema per type declaration, e.g.:
+//
+//	export const TurtleSchema = z.object({
+//		Name: z.string(),
+//		Tags: z.array(z.string()).nullable().optional(),
+//	});
+//	export type Turtle = z.infer<typeof TurtleSchema>;
+//
+// The TypeScript types are inferred from the schemas, thus the schemas and the types cannot drift
+// apart. The exceptions are recursive types, which zod cannot infer. Such types are declared
+// explicitly, and their schemas are annotated with said types and reference themselves via z.lazy().
+//
+// Schemas for generic types are functions of the schemas of their type arguments, e.g.:
+//
+//	export interface Page<T> {
+//		Items: T[] | null;
+//	}
+//	export const PageSchema = <T>(TSchema: z.ZodType<T>): z.ZodType<Page<T>> => z.object({
+//		Items: z.array(TSchema).nullable(),
+//	});
+//
+// which are referenced as e.g. PageSchema(ItemSchema).
+package zod
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/skia-dev/go2ts/typescript"
+)
+
+// Render writes zod schemas for the given TypeScript type declarations to the given io.Writer.
+//
+// Type declarations should be ordered such that any type declarations a given type declaration
+// depends on precede it, which is the order returned by go2ts.Go2TS.TypeDeclarations(). References
+// to type declarations that appear later (e.g. recursive types) are wrapped with z.lazy().
+func Render(w io.Writer, typeDeclarations []typescript.TypeDeclaration) error {
+	return RenderWithOptions(w, typeDeclarations, typescript.RenderOptions{})
+}
+
+// RenderWithOptions is like Render, but formats the TypeScript code as per the given options, e.g.
+// with double quotes and without semicolons.
+func RenderWithOptions(w io.Writer, typeDeclarations []typescript.TypeDeclaration, opts typescript.RenderOptions) error {
+	// Positions of the type declarations by qualified name, which determine whether a reference must
+	// be wrapped with z.lazy().
+	positions := map[string]int{}
+	for i, typeDeclaration := range typeDeclarations {
+		positions[typeDeclaration.QualifiedName()] = i
+	}
+
+	// Group the schemas by namespace, such that each namespace is rendered as a single namespace
+	// block.
+	var global typescript.Namespace
+	for i, typeDeclaration := range typeDeclarations {
+		r := &renderer{
+			position:  i,
+			positions: positions,
+			opts:      opts,
+		}
+		namespace, _ := splitQualifiedName(typeDeclaration.QualifiedName())
+		global.Add(namespace, r.renderTypeDeclaration(typeDeclaration))
+	}
+
+	code := fmt.Sprintf("import { z } from %s%s", opts.Quote("zod"), opts.Semicolon())
+	if schemas := global.ToTypeScriptWithOptions(opts); schemas != "" {
+		code += "\n\n" + schemas
+	}
+	_, err := io.WriteString(w, opts.File(code))
+	return err
+}
+
+// SchemaName returns the qualified name of the zod schema for the given type declaration, e.g.
+// MyNamespace.MyTypeSchema.
+func SchemaName(typeDeclaration typescript.TypeDeclaration) string {
+	return typeDeclaration.QualifiedName() + "Schema"
+}
+
+// renderer renders the zod schema for the type declaration at a given position.
+type renderer struct {
+	// position is the position of the type declaration being rendered.
+	position int
+
+	// positions maps the qualified names of all type declarations to their positions.
+	positions map[string]int
+
+	// lazy is set to true if the schema being rendered references any type declarations via z.lazy().
+	lazy bool
+
+	// opts determines how the TypeScript code is formatted.
+	opts typescript.RenderOptions
+}
+
+// renderTypeDeclaration returns the zod schema and inferred TypeScript type for the given type
+// declaration, without any namespace blocks.
+func (r *renderer) renderTypeDeclaration(typeDeclaration typescript.TypeDeclaration) string {
+	_, identifier := splitQualifiedName(typeDeclaration.QualifiedName())
+
+	var schema string
+	switch typeDeclaration := typeDeclaration.(type) {
+	case *typescript.InterfaceDeclaration:
+		schema = r.objectSchema(typeDeclaration)
+	case *typescript.TypeAliasDeclaration:
+		schema = r.schema(typeDeclaration.Type)
+	case *typescript.EnumDeclaration:
+		schema = fmt.Sprintf("z.nativeEnum(%s)", identifier)
+	default:
+		panic(fmt.Sprintf("Unknown TypeScript type declaration: %T.", typeDeclaration))
+	}
+
+	semicolon := r.opts.Semicolon()
+	var sb strings.Builder
+	typeParameters := typescript.TypeParameters(typeDeclaration)
+	switch typeDeclaration.(type) {
+	case *typescript.EnumDeclaration:
+		// Enums are runtime values, so they must be declared before their schemas.
+		sb.WriteString(withoutNamespace(typeDeclaration).ToTypeScriptWithOptions(r.opts))
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("export const %sSchema = %s%s", identifier, schema, semicolon))
+	default:
+		if len(typeParameters) > 0 {
+			// Zod cannot infer generic types, so we declare the type explicitly.
+			var typeParameterList, parameters []string
+			for _, typeParameter := range typeParameters {
+				typeParameterList = append(typeParameterList, typeParameter.Identifier)
+				parameters = append(parameters, fmt.Sprintf("%sSchema: z.ZodType<%s>", typeParameter.Identifier, typeParameter.Identifier))
+			}
+			typeParameterListString := strings.Join(typeParameterList, ", ")
+			sb.WriteString(withoutNamespace(typeDeclaration).ToTypeScriptWithOptions(r.opts))
+			sb.WriteString("\n")
+			sb.WriteString(fmt.Sprintf("export const %sSchema = <%s>(%s): z.ZodType<%s<%s>> => %s%s", identifier, typeParameterListString, strings.Join(parameters, ", "), identifier, typeParameterListString, schema, semicolon))
+		} else if r.lazy {
+			// Zod cannot infer recursive types, so we declare the type explicitly.
+			sb.WriteString(withoutNamespace(typeDeclaration).ToTypeScriptWithOptions(r.opts))
+			sb.WriteString("\n")
+			sb.WriteString(fmt.Sprintf("export const %sSchema: z.ZodType<%s> = %s%s", identifier, identifier, schema, semicolon))
+		} else {
+			sb.WriteString(fmt.Sprintf("export const %sSchema = %s%s\n", identifier, schema, semicolon))
+			sb.WriteString(fmt.Sprintf("export type %s = z.infer<typeof %sSchema>%s", identifier, identifier, semicolon))
+		}
+	}
+
+	return sb.String()
+}
+
+// objectSchema returns the zod object schema for the given interface declaration.
+func (r *renderer) objectSchema(interfaceDeclaration *typescript.InterfaceDeclaration) string {
+	if len(interfaceDeclaration.Properties) == 0 {
+		return "z.object({})"
+	}
+
+	// Zod can only make whole objects readonly, thus readonly properties are only reflected in the
+	// inferred type if all properties are readonly.
+	allReadonly := true
+	var sb strings.Builder
+	sb.WriteString("z.object({\n")
+	for _, prop := range interfaceDeclaration.Properties {
+		schema := r.schema(prop.Type)
+		if prop.Optional {
+			schema += ".optional()"
+		}
+		allReadonly = allReadonly && prop.Readonly
+		sb.WriteString(fmt.Sprintf("%s%s: %s,\n", r.opts.Indentation(), r.opts.PropertyName(prop.Identifier), schema))
+	}
+	sb.WriteString("})")
+	return withReadonly(sb.String(), allReadonly)
+}
+
+// schema returns the zod schema for the given type.
+func (r *renderer) schema(t typescript.Type) string {
+	switch t := t.(type) {
+	case typescript.BasicType:
+		switch t {
+		case typescript.Boolean:
+			return "z.boolean()"
+		case typescript.Number:
+			return "z.number()"
+		case typescript.String:
+			return "z.string()"
+		case typescript.Null:
+			return "z.null()"
+		case typescript.Any:
+			return "z.any()"
+		}
+	case *typescript.LiteralType:
+		return fmt.Sprintf("z.literal(%s)", t.ToTypeScriptWithOptions(r.opts))
+	case *typescript.ArrayType:
+		return withReadonly(fmt.Sprintf("z.array(%s)", r.schema(t.ItemsType)), t.Readonly)
+	case *typescript.TupleType:
+		schemas := make([]string, 0, len(t.ElementTypes))
+		for _, elementType := range t.ElementTypes {
+			schemas = append(schemas, r.schema(elementType))
+		}
+		return withReadonly(fmt.Sprintf("z.tuple([%s])", strings.Join(schemas, ", ")), t.Readonly)
+	case *typescript.MapType:
+		if t.IsMappedType() {
+			// Records with enum keys are partial, i.e. not all keys need to be present.
+			return withReadonly(fmt.Sprintf("z.record(%s, %s)", r.schema(t.IndexType), r.schema(t.ValueType)), t.Readonly)
+		}
+		// JSON object keys are always strings, even if the TypeScript index type is a number.
+		return withReadonly(fmt.Sprintf("z.record(z.string(), %s)", r.schema(t.ValueType)), t.Readonly)
+	case typescript.UnionType:
+		return r.unionSchema(t)
+	case *typescript.UnionType:
+		return r.unionSchema(*t)
+	case *typescript.TypeReference:
+		return r.referenceSchema(t.TypeDeclaration(), nil)
+	case *typescript.TypeParameter:
+		return t.Identifier + "Schema"
+	case *typescript.GenericTypeReference:
+		return r.referenceSchema(t.TypeReference.TypeDeclaration(), t.TypeArguments)
+	}
+	panic(fmt.Sprintf("Unknown TypeScript type: %T.", t))
+}
+
+// withReadonly appends .readonly() to the given schema if readonly is true, such that the inferred
+// type is readonly.
+func withReadonly(schema string, readonly bool) string {
+	if readonly {
+		return schema + ".readonly()"
+	}
+	return schema
+}
+
+// unionSchema returns the zod schema for the given union type.
+func (r *renderer) unionSchema(u typescript.UnionType) string {
+	// Unions with "any" accept anything.
+	for _, t := range u.Types {
+		if t == typescript.Any {
+			return "z.any()"
+		}
+	}
+
+	// Nillable Go types, e.g. slices, maps and pointers, are represented as "T | null".
+	var nonNullTypes []typescript.Type
+	for _, t := range u.Types {
+		if t != typescript.Null {
+			nonNullTypes = append(nonNullTypes, t)
+		}
+	}
+	if len(nonNullTypes) == 0 {
+		return "z.null()"
+	}
+	nullable := len(nonNullTypes) < len(u.Types)
+
+	var schema string
+	if len(nonNullTypes) == 1 {
+		schema = r.schema(nonNullTypes[0])
+	} else if literals, ok := stringLiterals(nonNullTypes, r.opts); ok {
+		schema = fmt.Sprintf("z.enum([%s])", strings.Join(literals, ", "))
+	} else {
+		var schemas []string
+		for _, t := range nonNullTypes {
+			schemas = append(schemas, r.schema(t))
+		}
+		schema = fmt.Sprintf("z.union([%s])", strings.Join(schemas, ", "))
+	}
+
+	if nullable {
+		schema += ".nullable()"
+	}
+	return schema
+}
+
+// referenceSchema returns the zod schema for a reference to the given type declaration, with the
+// given type arguments if it's generic.
+func (r *renderer) referenceSchema(typeDeclaration typescript.TypeDeclaration, typeArguments []typescript.Type) string {
+	schemaName := SchemaName(typeDeclaration)
+	if len(typeArguments) > 0 {
+		var schemas []string
+		for _, t := range typeArguments {
+			schemas = append(schemas, r.schema(t))
+		}
+		schemaName = fmt.Sprintf("%s(%s)", schemaName, strings.Join(schemas, ", "))
+	}
+	if position, ok := r.positions[typeDeclaration.QualifiedName()]; ok && position < r.position {
+		return schemaName
+	}
+	// The referenced schema is declared later (or it's the schema being rendered), so it must be
+	// referenced lazily.
+	r.lazy = true
+	return fmt.Sprintf("z.lazy(() => %s)", schemaName)
+}
+
+// stringLiterals returns the given types as TypeScript string literals formatted as per the given
+// options and true, or nil and false if any of the types is not a string literal type.
+func stringLiterals(types []typescript.Type, opts typescript.RenderOptions) ([]string, bool) {
+	var literals []string
+	for _, t := range types {
+		literal, ok := t.(*typescript.LiteralType)
+		if !ok || literal.BasicType != typescript.String {
+			return nil, false
+		}
+		literals = append(literals, literal.ToTypeScriptWithOptions(opts))
+	}
+	return literals, true
+}
+
+// withoutNamespace returns a copy of the given type declaration in the global namespace. This is
+// used to declare types inside the namespace block of their schemas.
+func withoutNamespace(typeDeclaration typescript.TypeDeclaration) typescript.TypeDeclaration {
+	switch typeDeclaration := typeDeclaration.(type) {
+	case *typescript.InterfaceDeclaration:
+		declaration := *typeDeclaration
+		declaration.Namespace = ""
+		return &declaration
+	case *typescript.TypeAliasDeclaration:
+		declaration := *typeDeclaration
+		declaration.Namespace = ""
+		return &declaration
+	case *typescript.EnumDeclaration:
+		declaration := *typeDeclaration
+		declaration.Namespace = ""
+		return &declaration
+	}
+	panic(fmt.Sprintf("Unknown TypeScript type declaration: %T.", typeDeclaration))
+}
+
+// splitQualifiedName splits a qualified TypeScript type name into its namespace and identifier.
+func splitQualifiedName(qualifiedName string) (namespace, identifier string) {
+	if i := strings.LastIndex(qualifiedName, "."); i >= 0 {
+		return qualifiedName[:i], qualifiedName[i+1:]
+	}
+	return "", qualifiedName
+}
@@ -0,0 +1,269 @@
+package zod
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/skia-dev/go2ts"
+	"github.com/skia-dev/go2ts/typescript"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_Go2TSTypes_Success(t *testing.T) {
+	type Direction string
+
+	type Position struct {
+		X int
+		Y int
+	}
+
+	type Turtle struct {
+		Position  Position `json:"Coordinates"`
+		Direction Direction
+		Tags      []string `json:",omitempty"`
+		Stats     map[string]float64
+		Parent    *Turtle
+		Extra     interface{}
+	}
+
+	g := go2ts.New()
+	g.Add(Turtle{})
+	g.AddUnion([]Direction{"up", "down"})
+	var b bytes.Buffer
+	require.NoError(t, Render(&b, g.TypeDeclarations()))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+import { z } from 'zod';
+
+export const PositionSchema = z.object({
+	X: z.number(),
+	Y: z.number(),
+});
+export type Position = z.infer<typeof PositionSchema>;
+
+export const DirectionSchema = z.enum(['up', 'down']);
+export type Direction = z.infer<typeof DirectionSchema>;
+
+export interface Turtle {
+	Coordinates: Position;
+	Direction: Direction;
+	Tags?: string[] | null;
+	Stats: { [key: string]: number } | null;
+	Parent: Turtle | null;
+	Extra: any;
+}
+export const TurtleSchema: z.ZodType<Turtle> = z.object({
+	Coordinates: PositionSchema,
+	Direction: DirectionSchema,
+	Tags: z.array(z.string()).nullable().optional(),
+	Stats: z.record(z.string(), z.number()).nullable(),
+	Parent: z.lazy(() => TurtleSchema).nullable(),
+	Extra: z.any(),
+});
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestRender_NamespacesEnumsAndUnions_Success(t *testing.T) {
+	speed := &typescript.EnumDeclaration{
+		Namespace:  "Foo",
+		Identifier: "Speed",
+		Members: []typescript.EnumMember{
+			{Identifier: "Slow", Value: &typescript.LiteralType{BasicType: typescript.Number, Literal: "1"}},
+		},
+	}
+	mixed := &typescript.TypeAliasDeclaration{
+		Namespace:  "Foo",
+		Identifier: "Mixed",
+		Type: &typescript.UnionType{
+			Types: []typescript.Type{
+				&typescript.LiteralType{BasicType: typescript.Number, Literal: "1"},
+				&typescript.LiteralType{BasicType: typescript.Boolean, Literal: "true"},
+				typescript.Null,
+			},
+		},
+	}
+	turtle := &typescript.InterfaceDeclaration{
+		Namespace:  "Foo",
+		Identifier: "Turtle",
+		Properties: []typescript.PropertySignature{
+			{Identifier: "Speed", Type: speed.TypeReference()},
+			{Identifier: "Mixed", Type: mixed.TypeReference()},
+			{Identifier: "Laps", Type: &typescript.MapType{IndexType: speed.TypeReference(), ValueType: typescript.Number}},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, Render(&b, []typescript.TypeDeclaration{speed, mixed, turtle}))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+import { z } from 'zod';
+
+export namespace Foo {
+	export enum Speed {
+		Slow = 1,
+	}
+	export const SpeedSchema = z.nativeEnum(Speed);
+
+	export const MixedSchema = z.union([z.literal(1), z.literal(true)]).nullable();
+	export type Mixed = z.infer<typeof MixedSchema>;
+
+	export const TurtleSchema = z.object({
+		Speed: Foo.SpeedSchema,
+		Mixed: Foo.MixedSchema,
+		Laps: z.record(Foo.SpeedSchema, z.number()),
+	});
+	export type Turtle = z.infer<typeof TurtleSchema>;
+}
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestRender_GenericTypes_SchemasAreFunctions(t *testing.T) {
+	item := &typescript.InterfaceDeclaration{
+		Identifier: "Item",
+		Properties: []typescript.PropertySignature{
+			{Identifier: "Name", Type: typescript.String},
+		},
+	}
+	itemsType := &typescript.TypeParameter{Identifier: "T"}
+	page := &typescript.InterfaceDeclaration{
+		Identifier:     "Page",
+		TypeParameters: []*typescript.TypeParameter{itemsType},
+	}
+	page.Properties = []typescript.PropertySignature{
+		{Identifier: "Items", Type: &typescript.ArrayType{ItemsType: itemsType}},
+		{Identifier: "Next", Type: &typescript.UnionType{Types: []typescript.Type{
+			&typescript.GenericTypeReference{TypeReference: page.TypeReference(), TypeArguments: []typescript.Type{itemsType}},
+			typescript.Null,
+		}}},
+	}
+	catalog := &typescript.InterfaceDeclaration{
+		Identifier: "Catalog",
+		Properties: []typescript.PropertySignature{
+			{Identifier: "Items", Type: &typescript.GenericTypeReference{TypeReference: page.TypeReference(), TypeArguments: []typescript.Type{item.TypeReference()}}},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, Render(&b, []typescript.TypeDeclaration{item, page, catalog}))
+	expected := `// DO NOT EDIT. This file is automatically generated.
+
+import { z } from 'zod';
+
+export const ItemSchema = z.object({
+	Name: z.string(),
+});
+export type Item = z.infer<typeof ItemSchema>;
+
+export interface Page<T> {
+	Items: T[];
+	Next: Page<T> | null;
+}
+export const PageSchema = <T>(TSchema: z.ZodType<T>): z.ZodType<Page<T>> => z.object({
+	Items: z.array(TSchema),
+	Next: z.lazy(() => PageSchema(TSchema)).nullable(),
+});
+
+export const CatalogSchema = z.object({
+	Items: PageSchema(ItemSchema),
+});
+export type Catalog = z.infer<typeof CatalogSchema>;
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestRender_ReadonlyTypes_ReadonlySchemas(t *testing.T) {
+	pond := &typescript.InterfaceDeclaration{
+		Identifier: "Pond",
+		Properties: []typescript.PropertySignature{
+			{Identifier: "Depth", Type: typescript.Number, Readonly: true},
+		},
+	}
+	lake := &typescript.InterfaceDeclaration{
+		Identifier: "Lake",
+		Properties: []typescript.PropertySignature{
+			{Identifier: "Ponds", Type: &typescript.ArrayType{ItemsType: pond.TypeReference(), Readonly: true}, Readonly: true},
+			{Identifier: "Depths", Type: &typescript.MapType{IndexType: typescript.String, ValueType: typescript.Number, Readonly: true}},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, Render(&b, []typescript.TypeDeclaration{pond, lake}))
+	assert.Contains(t, b.String(), `
+export const PondSchema = z.object({
+	Depth: z.number(),
+}).readonly();
+export type Pond = z.infer<typeof PondSchema>;
+
+export const LakeSchema = z.object({
+	Ponds: z.array(PondSchema).readonly(),
+	Depths: z.record(z.string(), z.number()).readonly(),
+});
+export type Lake = z.infer<typeof LakeSchema>;
+`)
+}
+
+func TestRender_TupleTypes_TupleSchemas(t *testing.T) {
+	turtle := &typescript.InterfaceDeclaration{
+		Identifier: "Turtle",
+		Properties: []typescript.PropertySignature{
+			{Identifier: "Position", Type: &typescript.TupleType{ElementTypes: []typescript.Type{typescript.Number, typescript.Number}}},
+			{Identifier: "Start", Type: &typescript.TupleType{ElementTypes: []typescript.Type{typescript.String}, Readonly: true}},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, Render(&b, []typescript.TypeDeclaration{turtle}))
+	assert.Contains(t, b.String(), `
+export const TurtleSchema = z.object({
+	Position: z.tuple([z.number(), z.number()]),
+	Start: z.tuple([z.string()]).readonly(),
+});
+`)
+}
+
+func TestRenderWithOptions_FormattedAsPerOptions(t *testing.T) {
+	direction := &typescript.TypeAliasDeclaration{
+		Namespace:  "compass",
+		Identifier: "Direction",
+		Type: &typescript.UnionType{
+			Types: []typescript.Type{
+				&typescript.LiteralType{BasicType: typescript.String, Literal: "up"},
+				&typescript.LiteralType{BasicType: typescript.String, Literal: "down"},
+			},
+		},
+	}
+	turtle := &typescript.InterfaceDeclaration{
+		Identifier: "Turtle",
+		Properties: []typescript.PropertySignature{
+			{Identifier: "Direction", Type: direction.TypeReference()},
+			{Identifier: "Shell", Type: &typescript.LiteralType{BasicType: typescript.String, Literal: "hard"}},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, RenderWithOptions(&b, []typescript.TypeDeclaration{direction, turtle}, typescript.RenderOptions{
+		Indent:         "  ",
+		Quotes:         typescript.DoubleQuotes,
+		OmitSemicolons: true,
+		Header:         "// Generated by go2ts.",
+	}))
+	expected := `// Generated by go2ts.
+
+import { z } from "zod"
+
+export namespace compass {
+  export const DirectionSchema = z.enum(["up", "down"])
+  export type Direction = z.infer<typeof DirectionSchema>
+}
+
+export const TurtleSchema = z.object({
+  Direction: compass.DirectionSchema,
+  Shell: z.literal("hard"),
+})
+export type Turtle = z.infer<typeof TurtleSchema>
+`
+	assert.Equal(t, expected, b.String())
+}